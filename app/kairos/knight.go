@@ -13,7 +13,9 @@ func NewKnight(name string, spritesheet *engine.SpriteSheet, x, y float64) *Knig
 		GridActor: engine.NewGridActor(name, spritesheet, x, y),
 	}
 }
-func (a *Knight) Update(args ...any) error {
+func (a *Knight) Update(ctx *engine.UpdateContext) error {
+	a.UpdateMove(ctx.Delta)
+	a.UpdateEffects(ctx.Delta)
 	return nil
 }
 
@@ -2,7 +2,10 @@ package main
 
 import (
 	"fmt"
+	"image"
+	"image/color"
 	"log"
+	"math"
 	"os"
 	"path/filepath"
 
@@ -14,6 +17,12 @@ import (
 const (
 	actorSpeed = 16
 
+	// maxCameraZoom is the maximum zoom for the camera.
+	maxCameraZoom = 4
+
+	// playerTag is the tag for the actor controlled by the player.
+	playerTag = "player"
+
 	tilemapWidth  = 30
 	tilemapHeight = 20
 	tilemapSize   = tilemapWidth * tilemapHeight
@@ -28,39 +37,71 @@ const (
 	screenHeight = (tilemapHeight * tileHeightInPixels) / 2
 )
 
+// Game structure defines the gameplay scene.
 type Game struct {
+	*engine.Scene
 	Tilemap    *engine.TilemapJSON // all tile sprites.
 	Actors     []engine.IActor     // all game actors.
 	Camera     *engine.Camera
-	colliders  []engine.ICollider
+	collision  *engine.CollisionWorld
 	tilegrid   *TileGrid
 	keyhandler *engine.KeyboardHandler
+	renderer   *engine.RenderQueue
+	registry   *engine.Registry
+	ctx        *engine.UpdateContext
+	clock      *engine.Clock
+}
+
+// updateCamera method zooms the camera in and out with the plus and minus
+// keys, and prints the tile under the cursor when the left mouse button is
+// clicked.
+func (g *Game) updateCamera() {
+	if g.ctx.Input.IsKeyJustPressed(ebiten.KeyEqual) {
+		g.Camera.SetZoom(math.Min(g.Camera.Zoom*2, maxCameraZoom))
+	} else if g.ctx.Input.IsKeyJustPressed(ebiten.KeyMinus) {
+		g.Camera.SetZoom(math.Max(g.Camera.Zoom/2, 1))
+	}
+	if g.ctx.Input.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		cursorX, cursorY := g.ctx.Input.CursorPosition()
+		tileX, tileY := g.tilegrid.GetTilePosFromCursor(g.Camera, cursorX, cursorY)
+		fmt.Printf("tile %d,%d\n", tileX, tileY)
+	}
 }
 
 func (g *Game) Update() error {
-	g.keyhandler.Update()
-	g.tilegrid.Update(g.Actors[0])
-	for _, actor := range g.Actors {
-		//actor.Update(tilemapWidthInPixels, tilemapHeightInPixels)
-		if actor.GetName() == "knight" {
-			g.Camera.FollowTo(actor.GetPos())
-			g.Camera.Constrain(tilemapWidthInPixels, tilemapHeightInPixels)
+	// key bindings and grid input are read once per tick, so they are not
+	// lost or repeated when the tick runs zero or several steps.
+	if err := g.keyhandler.Update(g.ctx); err != nil {
+		return err
+	}
+	g.tilegrid.ControlEntity(g.ctx)
+	g.updateCamera()
+	for steps := g.clock.AdvanceTick(); steps > 0; steps-- {
+		g.ctx.Advance(g.clock.GetStep())
+		g.registry.Each(func(obj engine.IBase) bool {
+			if entity, ok := obj.(engine.IEntity); ok {
+				entity.StorePrevPos()
+			}
+			return true
+		})
+		if err := g.tilegrid.TileGrid.Update(g.ctx); err != nil {
+			return err
 		}
+		g.collision.UpdateAll()
+		g.Camera.Update(g.clock.GetStep())
 	}
 	//g.tilegrid.ControlEntity(tilemapWidthInPixels, tilemapHeightInPixels, g.Actors[0])
 	return nil
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
-	g.Tilemap.Draw(screen, g.Camera)
+	g.Camera.Alpha = g.clock.GetAlpha()
+	g.Tilemap.Submit(g.renderer)
 	//for _, actor := range g.Actors {
 	//    actor.Draw(screen, g.Camera)
 	//}
-	g.tilegrid.Draw(screen, g.Camera)
-}
-
-func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
-	return screenWidth, screenHeight
+	g.tilegrid.Submit(g.renderer)
+	g.renderer.Draw(screen, g.Camera)
 }
 
 func main() {
@@ -82,13 +123,14 @@ func main() {
 	knightSpriteSheet.SetFrameMap(map[string][]int{
 		"down":  {0, 0, 0, 16, 0, 32, 0, 48},
 		"up":    {16, 0, 16, 16, 16, 32, 16, 48},
-		"left":  {32, 0, 32, 16, 32, 32, 32, 48},
 		"right": {48, 0, 48, 16, 48, 32, 48, 48},
 	})
+	knightSpriteSheet.SetFrameMirror("left", "right", engine.FlipHorizontal)
 	knightSpriteSheet.SetFrameType("down")
-	knightSpriteSheet.SetFrameSpeed(15)
+	knightSpriteSheet.SetFrameDuration(0.25)
 	knight := NewKnight("knight", knightSpriteSheet, 0, 0)
 	knight.SetSpeed(actorSpeed)
+	knight.SetTag(playerTag)
 
 	spiritPath := filepath.Join(wd, "assets/images/spirit_walk.png")
 	spiritSpriteSheetImage, _, err := ebitenutil.NewImageFromFile(spiritPath)
@@ -99,34 +141,51 @@ func main() {
 	spiritSpriteSheet.SetFrameMap(map[string][]int{
 		"down":  {0, 0, 0, 16, 0, 32, 0, 48},
 		"up":    {16, 0, 16, 16, 16, 32, 16, 48},
-		"left":  {32, 0, 32, 16, 32, 32, 32, 48},
 		"right": {48, 0, 48, 16, 48, 32, 48, 48},
 	})
+	spiritSpriteSheet.SetFrameMirror("left", "right", engine.FlipHorizontal)
 	spiritSpriteSheet.SetFrameType("down")
-	spiritSpriteSheet.SetFrameSpeed(15)
+	spiritSpriteSheet.SetFrameDuration(0.25)
 	spirit := NewSpirit("spirit", spiritSpriteSheet, 32, 32)
 
 	g := &Game{
+		Scene:      engine.NewScene("game"),
 		Tilemap:    tilemap,
 		Actors:     []engine.IActor{knight, spirit},
 		Camera:     engine.NewCamera(0, 0, screenWidth, screenHeight),
 		tilegrid:   NewTileGrid(tilemap),
 		keyhandler: engine.NewKeyboardHandler("keyhandler"),
+		renderer:   engine.NewRenderQueue().SetLayerYSort(engine.RenderLayerWorld, true),
 	}
 
+	// highlight the knight as the selected unit.
+	knight.GetEffects().Add(engine.NewOutlineEffect(color.White, 1, 0))
+
+	knight.SetTileGrid(g.tilegrid.TileGrid)
 	g.tilegrid.AddTileAt(0, 0, knight)
 	g.tilegrid.AddTileAt(2, 2, spirit)
 
+	g.registry = engine.NewRegistry()
+	g.collision = engine.NewCollisionWorld(engine.DefaultCollisionCellSize)
+	knight.SetCollisionWorld(g.collision)
 	for _, act := range g.Actors {
-		g.colliders = append(g.colliders, act)
+		g.registry.Add(act)
+		g.collision.Add(act)
 	}
+	g.clock = engine.NewClock(engine.DefaultFixedStep)
+	g.ctx = engine.NewUpdateContext(image.Rect(0, 0, int(tilemapWidthInPixels), int(tilemapHeightInPixels)), g.registry)
+	g.Camera.SetTarget(knight).
+		SetFollowMode(engine.FollowLerp, 8).
+		SetLimits(tilemapWidthInPixels, tilemapHeightInPixels)
 
 	g.keyhandler.AddKeyBindingForKey(ebiten.KeyC, nil, func() {
 		fmt.Println("ctrl-c was pressed")
 		os.Exit(0)
 	})
 
-	if err := ebiten.RunGame(g); err != nil {
+	manager := engine.NewSceneManager(screenWidth, screenHeight)
+	manager.Push(g, nil)
+	if err := ebiten.RunGame(manager); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -15,7 +15,9 @@ func NewSpirit(name string, spritesheet *engine.SpriteSheet, x, y float64) *Spir
 	return spirit
 }
 
-func (s *Spirit) Update(args ...any) error {
+func (s *Spirit) Update(ctx *engine.UpdateContext) error {
+	s.UpdateAnimation(ctx.Delta)
+	s.UpdateEffects(ctx.Delta)
 	return nil
 }
 
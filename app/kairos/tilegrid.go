@@ -2,7 +2,6 @@ package main
 
 import (
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/jrecuero/ebiplay/pkg/engine"
 )
 
@@ -16,26 +15,34 @@ func NewTileGrid(tilemap *engine.TilemapJSON) *TileGrid {
 	}
 }
 
-func (t *TileGrid) ControlEntity(args ...any) {
+// ControlEntity method moves the player actor, found in the context world by
+// its tag, with the arrow keys.
+func (t *TileGrid) ControlEntity(ctx *engine.UpdateContext) {
 	//x, y := entity.GetPos()
 	//tileX, tileY := t.GetTilePosFromScreenPos(x, y)
 	//key := GetKeyIntToString(tileX, tileY)
 	//_ = key
-	actor := args[0].(engine.IEntity)
+	obj, ok := ctx.World.FindFirstByTag(playerTag)
+	if !ok {
+		return
+	}
+	a, ok := obj.(engine.IGridActor)
+	if !ok {
+		return
+	}
 	tilemapWidthInPixels, tilemapHeightInPixels := t.GetTilemapJSON().GetTilemapSizeInPixels()
-	a := actor.(engine.IGridActor)
-	if inpututil.IsKeyJustPressed(ebiten.KeyRight) {
+	if ctx.Input.IsKeyJustPressed(ebiten.KeyRight) {
 		a.MoveUpdate("right", tilemapWidthInPixels, tilemapHeightInPixels)
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyLeft) {
+	} else if ctx.Input.IsKeyJustPressed(ebiten.KeyLeft) {
 		a.MoveUpdate("left", tilemapWidthInPixels, tilemapHeightInPixels)
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyUp) {
+	} else if ctx.Input.IsKeyJustPressed(ebiten.KeyUp) {
 		a.MoveUpdate("up", tilemapWidthInPixels, tilemapHeightInPixels)
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyDown) {
+	} else if ctx.Input.IsKeyJustPressed(ebiten.KeyDown) {
 		a.MoveUpdate("down", tilemapWidthInPixels, tilemapHeightInPixels)
 	}
 }
 
-func (t *TileGrid) Update(args ...any) error {
-	t.ControlEntity(args...)
-	return t.TileGrid.Update(args...)
+func (t *TileGrid) Update(ctx *engine.UpdateContext) error {
+	t.ControlEntity(ctx)
+	return t.TileGrid.Update(ctx)
 }
@@ -2,17 +2,23 @@ package main
 
 import (
 	"fmt"
+	"image"
+	"image/color"
 	"log"
 	"path/filepath"
-	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/jrecuero/ebiplay/pkg/engine"
+	"github.com/jrecuero/ebiplay/pkg/tools"
 )
 
 const (
-	actorSpeed = 16
+	// actorSpeed is the player speed in pixels per second.
+	actorSpeed = 960
+
+	// playerTag is the tag for the actor controlled by the player.
+	playerTag = "player"
 
 	tilemapWidth  = 30
 	tilemapHeight = 20
@@ -24,8 +30,17 @@ const (
 	tilemapWidthInPixels  float64 = tilemapWidth * tileWidthInPixels
 	tilemapHeightInPixels float64 = tilemapHeight * tileHeightInPixels
 
+	// tallTilesLayer is the tilemap layer with tiles taller than actors, like
+	// trees, drawn Y-sorted with actors so they can walk behind them.
+	tallTilesLayer = 1
+
 	screenWidth  = (tilemapWidth * tileWidthInPixels) / 2
 	screenHeight = (tilemapHeight * tileHeightInPixels) / 2
+
+	// eventPanDuration is the time, in seconds, the camera takes to pan to an
+	// event and back, and eventPanHold is the time it stays on the event.
+	eventPanDuration = 0.5
+	eventPanHold     = 0.75
 )
 
 type Event struct {
@@ -33,96 +48,153 @@ type Event struct {
 	*engine.Event
 }
 
+// NewEvent function creates a new Event instance, a trigger that handles the
+// event when an actor goes through it, without blocking it.
 func NewEvent(name string, x, y float64, w, h int) *Event {
 	return &Event{
-		SolidEntity: engine.NewSolidEntity(name, x, y, w, h),
+		SolidEntity: engine.NewSolidEntity(name, x, y, w, h).SetTrigger(true),
 		Event:       &engine.Event{},
 	}
 }
 
-func (e *Event) CollideWith(other engine.IEntity) {
-	e.HandleEvent(other)
-}
-
 func (e *Event) HandleEvent(args ...any) error {
 	if !e.IsTriggered() {
 		e.SetTriggered(true)
 		other := args[0].(engine.IEntity)
 		fmt.Printf("Handle event %s collide with %s\n", e.GetName(), other.GetName())
-		go func() {
-			time.Sleep(5 * time.Second)
-			fmt.Printf("reset event %s\n", e.GetName())
-			e.SetTriggered(false)
-		}()
 	}
 	return nil
 }
 
+// OnCollisionEnter method handles the event once when an actor enters the
+// trigger.
+func (e *Event) OnCollisionEnter(other engine.ICollider) {
+	e.HandleEvent(other)
+}
+
+// OnCollisionExit method resets the event when the actor leaves the trigger.
+func (e *Event) OnCollisionExit(other engine.ICollider) {
+	fmt.Printf("reset event %s\n", e.GetName())
+	e.SetTriggered(false)
+}
+
+// Game structure defines the gameplay scene.
 type Game struct {
+	*engine.Scene
 	Tilemap   *engine.TilemapJSON
-	Actors    []engine.IActor
+	registry  *engine.Registry
+	ctx       *engine.UpdateContext
+	clock     *engine.Clock
 	Camera    *engine.Camera
 	menu      *engine.Menu
-	colliders []engine.ICollider
+	collision *engine.CollisionWorld
+	renderer  *engine.RenderQueue
+	dust      *engine.ParticleEmitter
 }
 
-func checkHorizontalCollision(actor engine.IActor, colliders []engine.ICollider) {
-	x, y := actor.GetPos()
-	dx := actor.GetDx()
-	for _, coll := range colliders {
-		if collActor, ok := coll.(engine.IActor); ok && actor == collActor {
-			continue
-		}
-		if actor.GetBounds().Overlaps(coll.GetBounds()) {
-			actor.CollideWith(coll.(engine.IEntity))
-			coll.CollideWith(actor)
-			actor.SetPos(x-dx, y)
+// logCollision function prints the colliders in a collision published in
+// the event bus.
+func logCollision(topic string, payload any) {
+	event := payload.(engine.CollisionEvent)
+	fmt.Printf("%s %s with %s\n", topic, event.A.(engine.IEntity).GetName(), event.B.(engine.IEntity).GetName())
+}
+
+// onEventEnter method shakes the camera and flashes the screen when an actor
+// enters an event, and then shows the event with a short camera pan.
+func (g *Game) onEventEnter(topic string, payload any) {
+	collision := payload.(engine.CollisionEvent)
+	event, ok := collision.A.(*Event)
+	if !ok {
+		if event, ok = collision.B.(*Event); !ok {
+			return
 		}
 	}
+	g.Camera.AddTrauma(0.5)
+	g.Camera.Flash(color.White, 0.25)
+	g.Camera.PanToEntity(event, eventPanDuration).
+		SetEase(tools.EaseInOutCubic).
+		SetHold(eventPanHold).
+		OnComplete(func() { g.Camera.ReturnToFollow(eventPanDuration).SetEase(tools.EaseInOutCubic) })
 }
 
-func checkVerticalCollision(actor engine.IActor, colliders []engine.ICollider) {
-	x, y := actor.GetPos()
-	dy := actor.GetDy()
-	for _, coll := range colliders {
-		if collActor, ok := coll.(engine.IActor); ok && actor == collActor {
-			continue
-		}
-		if actor.GetBounds().Overlaps(coll.GetBounds()) {
-			fmt.Printf("collision %s with %s\n", actor.GetName(), coll.(engine.IEntity).GetName())
-			actor.CollideWith(coll.(engine.IEntity))
-			coll.CollideWith(actor)
-			actor.SetPos(x, y-dy)
-		}
+// updateDust method emits dust at the feet of the given actor while it is
+// moving. The emitter is attached to the actor, so it follows it.
+func (g *Game) updateDust(actor engine.IActor) {
+	if actor.GetDx() != 0 || actor.GetDy() != 0 {
+		g.dust.Start()
+	} else {
+		g.dust.Stop()
 	}
+	g.dust.Update(g.ctx.Delta)
 }
 
-func (g *Game) Update() error {
-	for _, actor := range g.Actors {
-		actor.Update(tilemapWidthInPixels, tilemapHeightInPixels)
-		if actor.GetName() == "knight" {
-			g.Camera.FollowTo(actor.GetPos())
-			g.Camera.Constrain(tilemapWidthInPixels, tilemapHeightInPixels)
-		}
+// addCollider method is called when an object is added to the registry, to
+// add it to the collision world.
+func (g *Game) addCollider(obj engine.IBase) {
+	if collider, ok := engine.CheckCollidable(obj); ok {
+		g.collision.Add(collider)
+	}
+}
 
-		checkHorizontalCollision(actor, g.colliders)
+// removeCollider method is called when an object is removed from the
+// registry, to remove it from the collision world.
+func (g *Game) removeCollider(obj engine.IBase) {
+	if collider, ok := engine.CheckCollidable(obj); ok {
+		g.collision.Remove(collider)
+	}
+}
 
-		checkVerticalCollision(actor, g.colliders)
+// step method runs one fixed simulation step.
+func (g *Game) step() error {
+	g.ctx.Advance(g.clock.GetStep())
+	var err error
+	g.registry.Each(func(obj engine.IBase) bool {
+		if actor, ok := obj.(engine.IActor); ok {
+			actor.StorePrevPos()
+			if err = actor.Update(g.ctx); err != nil {
+				return false
+			}
+			g.collision.Update(actor)
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	g.collision.UpdateContacts()
+	g.Camera.Update(g.clock.GetStep())
+	if obj, ok := g.registry.FindFirstByTag(playerTag); ok {
+		g.updateDust(obj.(engine.IActor))
 	}
-	//g.menu.Update()
 	return nil
 }
 
-func (g *Game) Draw(screen *ebiten.Image) {
-	g.Tilemap.Draw(screen, g.Camera)
-	for _, actor := range g.Actors {
-		actor.Draw(screen, g.Camera)
+func (g *Game) Update() error {
+	if g.ctx.Input.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.GetManager().Push(NewPauseScene(g.menu), engine.NewCrossfadeTransition(pauseTransitionDuration))
+		return nil
+	}
+	for steps := g.clock.AdvanceTick(); steps > 0; steps-- {
+		if err := g.step(); err != nil {
+			return err
+		}
 	}
-	g.menu.Draw(screen)
+	return nil
 }
 
-func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
-	return screenWidth, screenHeight
+func (g *Game) Draw(screen *ebiten.Image) {
+	g.Camera.Alpha = g.clock.GetAlpha()
+	g.Tilemap.Submit(g.renderer)
+	g.registry.Each(func(obj engine.IBase) bool {
+		// actors are submitted with all their children, like the dust
+		// emitter attached to the player.
+		if node, ok := obj.(engine.ISceneNode); ok && node.GetParent() == nil {
+			engine.SubmitSceneGraph(node, g.renderer, engine.RenderLayerWorld)
+		}
+		return true
+	})
+	g.renderer.Draw(screen, g.Camera)
+	g.Camera.DrawOverlay(screen)
 }
 
 func main() {
@@ -134,12 +206,14 @@ func main() {
 	wd := "./"
 	tilemapPath := filepath.Join(wd, "assets/tilemaps/tilemap.tmj")
 	tilemapSpriteSheetPath := filepath.Join(wd, "assets/tilemaps/tileset.tsj")
+	particlePresetsPath := filepath.Join(wd, "assets/particles/presets.json")
 	//imagePath := filepath.Join(wd, "assets/images/TilesetFloor.png")
 
 	//tileSpriteSheet := engine.NewTileSpriteSheet(tilemapSpriteSheetPath)
 	//fmt.Printf("%#+v\n", tileSpriteSheet)
 
 	tilemap := engine.NewTilemapJSON(tilemapPath, tilemapSpriteSheetPath)
+	tilemap.SetLayerRender(tallTilesLayer, engine.RenderLayerWorld, true)
 
 	ebiten.SetWindowSize(screenWidth*2, screenHeight*2)
 	ebiten.SetWindowTitle("TileMap Demo")
@@ -153,13 +227,14 @@ func main() {
 	knightSpriteSheet.SetFrameMap(map[string][]int{
 		"down":  {0, 0, 0, 16, 0, 32, 0, 48},
 		"up":    {16, 0, 16, 16, 16, 32, 16, 48},
-		"left":  {32, 0, 32, 16, 32, 32, 32, 48},
 		"right": {48, 0, 48, 16, 48, 32, 48, 48},
 	})
+	knightSpriteSheet.SetFrameMirror("left", "right", engine.FlipHorizontal)
 	knightSpriteSheet.SetFrameType("down")
-	knightSpriteSheet.SetFrameSpeed(15)
+	knightSpriteSheet.SetFrameDuration(0.25)
 	knight := NewKnight("knight", knightSpriteSheet, 0, 0)
 	knight.SetSpeed(actorSpeed)
+	knight.SetTag(playerTag)
 
 	spiritPath := filepath.Join(wd, "assets/images/spirit_idle.png")
 	spiritSpriteSheetImage, _, err := ebitenutil.NewImageFromFile(spiritPath)
@@ -170,7 +245,7 @@ func main() {
 	spiritSpriteSheet.SetFrameMap(map[string][]int{
 		"idle": {0, 0, 50, 0, 100, 0, 150, 0, 200, 0},
 	})
-	spiritSpriteSheet.SetFrameSpeed(15)
+	spiritSpriteSheet.SetFrameDuration(0.25)
 	spirit := NewSpirit("spirit", spiritSpriteSheet, 30, 30)
 
 	// male warrior
@@ -183,14 +258,14 @@ func main() {
 	warriorSpriteSheet.SetFrameMap(map[string][]int{
 		"down":         {0, 512, 512, 512, 1024, 512, 1536, 512},
 		"up":           {0, 2560, 512, 2560, 1024, 2560, 1536, 2560},
-		"left":         {0, 4608, 512, 4608, 1024, 4608, 1536, 4608},
 		"right":        {0, 6656, 512, 6656, 1024, 6656, 1536, 6656},
 		"attack/down":  {0, 1024, 512, 1024, 1024, 1024, 1536, 1024},
 		"attack/up":    {0, 3072, 512, 3072, 1024, 3072, 1536, 3072},
-		"attack/left":  {0, 5120, 512, 5120, 1024, 5120, 1536, 5120},
 		"attack/right": {0, 7168, 512, 7168, 1024, 7168, 1536, 7168},
 	})
-	warriorSpriteSheet.SetFrameSpeed(15)
+	warriorSpriteSheet.SetFrameMirror("left", "right", engine.FlipHorizontal)
+	warriorSpriteSheet.SetFrameMirror("attack/left", "attack/right", engine.FlipHorizontal)
+	warriorSpriteSheet.SetFrameDuration(0.25)
 	warrior := NewWarrior("warrior", warriorSpriteSheet, 0, 0)
 	_ = warrior
 
@@ -201,22 +276,43 @@ func main() {
 	}
 
 	g := &Game{
+		Scene:   engine.NewScene("game"),
 		Tilemap: tilemap,
-		Actors:  []engine.IActor{knight, spirit},
-		//Actors: []engine.IActor{warrior},
-		Camera: engine.NewCamera(0, 0, screenWidth, screenHeight),
+		Camera:  engine.NewCamera(0, 0, screenWidth, screenHeight),
 		//warriorSpriteSheet: warriorSpriteSheet,
-		menu: engine.NewSubMenu("menu", 10, 10, 100, 4, menuitems, 0, nil),
+		menu:      engine.NewSubMenu("menu", 10, 10, 100, 4, menuitems, 0, nil),
+		renderer:  engine.NewRenderQueue().SetLayerYSort(engine.RenderLayerWorld, true),
+		collision: engine.NewCollisionWorld(engine.DefaultCollisionCellSize),
 	}
 
-	for _, act := range g.Actors {
-		g.colliders = append(g.colliders, act)
+	particlePresets := engine.LoadParticlePresetsJSON(particlePresetsPath)
+	// dust is emitted at the knight feet, relative to its top-left corner.
+	g.dust = engine.NewParticleEmitter("dust", particlePresets["dust"], tileWidthInPixels/2, tileHeightInPixels)
+	if err := engine.AddChild(knight, g.dust); err != nil {
+		log.Fatal(err)
 	}
 
-	event := NewEvent("event", 0, 60, 16, 16)
-	g.colliders = append(g.colliders, event)
+	g.registry = engine.NewRegistry().OnAdded(g.addCollider).OnRemoved(g.removeCollider)
+	g.registry.Add(knight)
+	g.registry.Add(spirit)
+	//g.registry.Add(warrior)
+	g.registry.Add(NewEvent("event", 0, 60, 16, 16))
+	g.clock = engine.NewClock(engine.DefaultFixedStep)
+	g.ctx = engine.NewUpdateContext(image.Rect(0, 0, int(tilemapWidthInPixels), int(tilemapHeightInPixels)), g.registry)
+	g.Camera.SetTarget(knight).
+		SetFollowMode(engine.FollowDamped, 0.2).
+		SetDeadZone(32, 24).
+		SetLookAhead(24, 3).
+		SetLimits(tilemapWidthInPixels, tilemapHeightInPixels)
+	g.ctx.Collision = g.collision
+	bus := engine.NewEventBus()
+	bus.Subscribe(engine.CollisionEnterTopic, logCollision)
+	bus.Subscribe(engine.CollisionEnterTopic, g.onEventEnter)
+	g.collision.SetEventBus(bus)
 
-	if err := ebiten.RunGame(g); err != nil {
+	manager := engine.NewSceneManager(screenWidth, screenHeight)
+	manager.Push(g, nil)
+	if err := ebiten.RunGame(manager); err != nil {
 		log.Fatal(err)
 	}
 }
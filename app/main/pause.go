@@ -0,0 +1,52 @@
+package main
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/jrecuero/ebiplay/pkg/engine"
+)
+
+const (
+	// pauseTransitionDuration is the time, in seconds, to show or hide the
+	// pause scene.
+	pauseTransitionDuration = 0.2
+)
+
+// PauseScene structure defines the scene pushed over the gameplay when the
+// game is paused. Gameplay is still drawn below, dimmed, but it is not
+// updated.
+type PauseScene struct {
+	*engine.Scene
+	menu *engine.Menu
+	ctx  *engine.UpdateContext
+}
+
+func NewPauseScene(menu *engine.Menu) *PauseScene {
+	scene := &PauseScene{
+		Scene: engine.NewScene("pause"),
+		menu:  menu,
+		ctx:   engine.NewUpdateContext(image.Rectangle{}, nil),
+	}
+	scene.SetTransparent(true)
+	return scene
+}
+
+func (s *PauseScene) Draw(screen *ebiten.Image) {
+	bounds := screen.Bounds()
+	vector.DrawFilledRect(screen, 0, 0, float32(bounds.Dx()), float32(bounds.Dy()), color.RGBA{A: 0x80}, false)
+	s.menu.Draw(screen)
+}
+
+func (s *PauseScene) Update() error {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		s.GetManager().Pop(engine.NewCrossfadeTransition(pauseTransitionDuration))
+		return nil
+	}
+	return s.menu.Update(s.ctx.Advance(1 / float64(ebiten.TPS())))
+}
+
+var _ engine.IScene = (*PauseScene)(nil)
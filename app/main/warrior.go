@@ -1,12 +1,9 @@
 package main
 
 import (
-	"fmt"
-	"strings"
-
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/jrecuero/ebiplay/pkg/engine"
+	"github.com/jrecuero/ebiplay/pkg/tools"
 )
 
 type Warrior struct {
@@ -17,15 +14,32 @@ func NewWarrior(name string, spritesheet *engine.SpriteSheet, x, y float64) *War
 	warrior := &Warrior{
 		Actor: engine.NewActor(name, spritesheet, x, y),
 	}
-	warrior.SetScale(0.08).SetSpeed(2.0)
+	warrior.SetPivot(0.5, 1.0).SetScale(0.08).SetSpeed(120)
+	warrior.SetAnimator(newWarriorAnimator(spritesheet))
+	// hitbox covers the warrior body, relative to the feet pivot in sprite
+	// pixels, without the transparent padding in the 512px frame.
+	warrior.SetHitbox(tools.NewCapsule(0, -320, 0, -96, 96))
 	return warrior
 }
 
+// newWarriorAnimator function creates the animation controller for the
+// warrior, which moves in any direction and plays a full attack animation
+// when the attack is triggered.
+func newWarriorAnimator(spritesheet *engine.SpriteSheet) *engine.Animator {
+	animator := engine.NewAnimator("warrior animator", spritesheet)
+	animator.SetParam("direction", "down")
+	animator.AddState("move", "{direction}")
+	animator.AddState("attack", "attack/{direction}")
+	animator.AddTransition("move", "attack").SetTrigger("attack")
+	animator.AddTransition("attack", "move").SetExitTime(1)
+	return animator
+}
+
 //func isWarriorInsideTilemapBoundary(x, y, width, height, tileWidth, tileHeight float64) bool {
 //    return (x >= 0) && (x < width-tileWidth) && (y >= 0) && (y < height-tileHeight)
 //}
 
-func (k *Warrior) Update(args ...any) error {
+func (k *Warrior) Update(ctx *engine.UpdateContext) error {
 	//tilemapWidthInPixels := args[0].(float64)
 	//tilemapHeightInPixels := args[1].(float64)
 	//x, y := k.GetPos()
@@ -58,15 +72,11 @@ func (k *Warrior) Update(args ...any) error {
 	//        k.GetSpriteSheet().UpdateFrameType(newFrameType)
 	//    }
 	//}
-	if err := k.Actor.Update(args...); err != nil {
+	if err := k.Actor.Update(ctx); err != nil {
 		return err
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
-		frameType := k.GetSpriteSheet().GetFrameType()
-		if !strings.Contains(frameType, "attack") {
-			newFrameType := fmt.Sprintf("attack/%s", frameType)
-			k.GetSpriteSheet().UpdateFrameType(newFrameType)
-		}
+	if ctx.Input.IsKeyJustPressed(ebiten.KeyEnter) {
+		k.GetAnimator().SetTrigger("attack")
 	}
 	return nil
 }
@@ -0,0 +1,88 @@
+package ecs
+
+import (
+	"reflect"
+	"slices"
+)
+
+// queryEntities function returns the entities in the world that have all the
+// given component stores, sorted by ID. The smallest store is used to
+// iterate, and the result is a copy, so entities and components can be
+// added or removed while the query results are processed.
+func queryEntities(stores ...iStore) []EntityID {
+	smallest := stores[0]
+	for _, store := range stores[1:] {
+		if len(store.getEntities()) < len(smallest.getEntities()) {
+			smallest = store
+		}
+	}
+	var result []EntityID
+	for _, id := range smallest.getEntities() {
+		matched := true
+		for _, store := range stores {
+			if !store.has(id) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// Query function returns the entities in the world that have components for
+// all the given types, sorted by ID.
+func Query(world *World, types ...reflect.Type) []EntityID {
+	if len(types) == 0 {
+		return slices.Clone(world.entities)
+	}
+	stores := make([]iStore, 0, len(types))
+	for _, key := range types {
+		store, ok := world.stores[key]
+		if !ok {
+			return nil
+		}
+		stores = append(stores, store)
+	}
+	return queryEntities(stores...)
+}
+
+// Each1 function calls the given function for every entity that has a
+// component of type A, in ascending ID order.
+func Each1[A any](world *World, visit func(EntityID, *A)) {
+	storeA := getStore[A](world)
+	for _, id := range queryEntities(storeA) {
+		if a, ok := storeA.Get(id); ok {
+			visit(id, a)
+		}
+	}
+}
+
+// Each2 function calls the given function for every entity that has
+// components of types A and B, in ascending ID order.
+func Each2[A, B any](world *World, visit func(EntityID, *A, *B)) {
+	storeA, storeB := getStore[A](world), getStore[B](world)
+	for _, id := range queryEntities(storeA, storeB) {
+		a, okA := storeA.Get(id)
+		b, okB := storeB.Get(id)
+		if okA && okB {
+			visit(id, a, b)
+		}
+	}
+}
+
+// Each3 function calls the given function for every entity that has
+// components of types A, B and C, in ascending ID order.
+func Each3[A, B, C any](world *World, visit func(EntityID, *A, *B, *C)) {
+	storeA, storeB, storeC := getStore[A](world), getStore[B](world), getStore[C](world)
+	for _, id := range queryEntities(storeA, storeB, storeC) {
+		a, okA := storeA.Get(id)
+		b, okB := storeB.Get(id)
+		c, okC := storeC.Get(id)
+		if okA && okB && okC {
+			visit(id, a, b, c)
+		}
+	}
+}
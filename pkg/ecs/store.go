@@ -0,0 +1,124 @@
+package ecs
+
+import (
+	"reflect"
+	"slices"
+)
+
+// iStore interface defines the component storage operations that do not
+// depend on the component type, so the world can handle all stores together.
+type iStore interface {
+	getEntities() []EntityID
+	has(EntityID) bool
+	remove(EntityID)
+}
+
+// Store structure defines the storage for all components of a given type.
+// Components are indexed by entity ID, and entity IDs are kept sorted, so
+// iteration order is deterministic.
+type Store[T any] struct {
+	components map[EntityID]*T
+	entities   []EntityID
+}
+
+// newStore function creates a new Store instance.
+func newStore[T any]() *Store[T] {
+	return &Store[T]{
+		components: make(map[EntityID]*T),
+	}
+}
+
+// getStore function returns the store for components of type T in the given
+// world, creating it the first time it is required.
+func getStore[T any](world *World) *Store[T] {
+	key := reflect.TypeFor[T]()
+	if store, ok := world.stores[key]; ok {
+		return store.(*Store[T])
+	}
+	store := newStore[T]()
+	world.stores[key] = store
+	return store
+}
+
+// -----------------------------------------------------------------------------
+// Store private methods
+// -----------------------------------------------------------------------------
+
+func (s *Store[T]) getEntities() []EntityID {
+	return s.entities
+}
+
+func (s *Store[T]) has(id EntityID) bool {
+	_, ok := s.components[id]
+	return ok
+}
+
+func (s *Store[T]) remove(id EntityID) {
+	if _, ok := s.components[id]; !ok {
+		return
+	}
+	delete(s.components, id)
+	if index, ok := slices.BinarySearch(s.entities, id); ok {
+		s.entities = slices.Delete(s.entities, index, index+1)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Store public methods
+// -----------------------------------------------------------------------------
+
+// Get method returns the component for the given entity.
+func (s *Store[T]) Get(id EntityID) (*T, bool) {
+	component, ok := s.components[id]
+	return component, ok
+}
+
+func (s *Store[T]) Len() int {
+	return len(s.entities)
+}
+
+// Set method adds or replaces the component for the given entity.
+func (s *Store[T]) Set(id EntityID, component T) *T {
+	if current, ok := s.components[id]; ok {
+		*current = component
+		return current
+	}
+	value := &component
+	s.components[id] = value
+	index, _ := slices.BinarySearch(s.entities, id)
+	s.entities = slices.Insert(s.entities, index, id)
+	return value
+}
+
+// -----------------------------------------------------------------------------
+// Component functions
+// -----------------------------------------------------------------------------
+
+// AddComponent function adds a component of type T to the given entity. If
+// the entity already has a component of that type, it is replaced. It returns
+// a pointer to the stored component, which can be modified in place.
+func AddComponent[T any](world *World, id EntityID, component T) *T {
+	if !world.IsAlive(id) {
+		return nil
+	}
+	return getStore[T](world).Set(id, component)
+}
+
+// GetComponent function returns the component of type T for the given entity.
+func GetComponent[T any](world *World, id EntityID) (*T, bool) {
+	return getStore[T](world).Get(id)
+}
+
+// HasComponent function returns if the given entity has a component of type
+// T.
+func HasComponent[T any](world *World, id EntityID) bool {
+	return getStore[T](world).has(id)
+}
+
+// RemoveComponent function removes the component of type T from the given
+// entity.
+func RemoveComponent[T any](world *World, id EntityID) {
+	getStore[T](world).remove(id)
+}
+
+var _ iStore = (*Store[int])(nil)
@@ -0,0 +1,22 @@
+package ecs
+
+// ISystem interface defines the behavior that runs every update over the
+// entities in the world.
+type ISystem interface {
+	Update(*World, float64) error
+}
+
+// SystemFunc type allows plain functions to be used as systems.
+type SystemFunc func(*World, float64) error
+
+func (f SystemFunc) Update(world *World, dt float64) error {
+	return f(world, dt)
+}
+
+// systemEntry structure keeps the system with its priority.
+type systemEntry struct {
+	system   ISystem
+	priority int
+}
+
+var _ ISystem = SystemFunc(nil)
@@ -0,0 +1,118 @@
+// Package ecs provides an entity-component-system layer: entities are plain
+// IDs, data is stored in typed components and behavior is implemented by
+// systems that run over entities with a given set of components.
+//
+// The package does not depend on the engine, so it can be used and tested on
+// its own. Engine adapters allow existing actors to be part of a world.
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// EntityID is the identifier for every entity in a world. Zero is never used
+// as a valid entity ID.
+type EntityID uint64
+
+// World structure holds all entities, their components and the systems that
+// run over them.
+type World struct {
+	nextID   EntityID
+	entities []EntityID
+	stores   map[reflect.Type]iStore
+	systems  []*systemEntry
+	sorted   bool
+}
+
+// NewWorld function creates a new World instance.
+func NewWorld() *World {
+	return &World{
+		stores: make(map[reflect.Type]iStore),
+		sorted: true,
+	}
+}
+
+// -----------------------------------------------------------------------------
+// World public methods
+// -----------------------------------------------------------------------------
+
+// AddSystem method adds a system to the world. Systems run in ascending
+// priority order, and systems with the same priority run in the order they
+// were added.
+func (w *World) AddSystem(system ISystem, priority int) *World {
+	w.systems = append(w.systems, &systemEntry{
+		system:   system,
+		priority: priority,
+	})
+	w.sorted = false
+	return w
+}
+
+// DestroyEntity method removes the entity and all its components from the
+// world.
+func (w *World) DestroyEntity(id EntityID) {
+	index, ok := slices.BinarySearch(w.entities, id)
+	if !ok {
+		return
+	}
+	w.entities = slices.Delete(w.entities, index, index+1)
+	for _, store := range w.stores {
+		store.remove(id)
+	}
+}
+
+// GetEntities method returns all entities alive in the world, in creation
+// order.
+func (w *World) GetEntities() []EntityID {
+	return w.entities
+}
+
+// IsAlive method returns if the entity exists in the world.
+func (w *World) IsAlive(id EntityID) bool {
+	_, ok := slices.BinarySearch(w.entities, id)
+	return ok
+}
+
+func (w *World) Len() int {
+	return len(w.entities)
+}
+
+// NewEntity method creates a new entity without components.
+func (w *World) NewEntity() EntityID {
+	w.nextID++
+	w.entities = append(w.entities, w.nextID)
+	return w.nextID
+}
+
+// RemoveSystem method removes the given system from the world. Systems have
+// to be comparable to be removed, so a SystemFunc can not be removed.
+func (w *World) RemoveSystem(system ISystem) {
+	if !reflect.TypeOf(system).Comparable() {
+		return
+	}
+	w.systems = slices.DeleteFunc(w.systems, func(entry *systemEntry) bool {
+		return entry.system == system
+	})
+}
+
+// Update method runs all systems in priority order with the given elapsed
+// time in seconds. It stops at the first system that returns an error.
+func (w *World) Update(dt float64) error {
+	if !w.sorted {
+		slices.SortStableFunc(w.systems, func(a, b *systemEntry) int {
+			return a.priority - b.priority
+		})
+		w.sorted = true
+	}
+	// systems added or removed by other systems take effect in the next
+	// update.
+	systems := slices.Clone(w.systems)
+	for _, entry := range systems {
+		if err := entry.system.Update(w, dt); err != nil {
+			return fmt.Errorf("system %T: %w", entry.system, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package ecs_test
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/jrecuero/ebiplay/pkg/ecs"
+)
+
+type position struct {
+	X, Y float64
+}
+
+type velocity struct {
+	DX, DY float64
+}
+
+func TestComponents(t *testing.T) {
+	world := ecs.NewWorld()
+	id := world.NewEntity()
+	ecs.AddComponent(world, id, position{X: 1, Y: 2})
+	if !ecs.HasComponent[position](world, id) {
+		t.Fatalf("entity %d expected to have position", id)
+	}
+	if ecs.HasComponent[velocity](world, id) {
+		t.Errorf("entity %d not expected to have velocity", id)
+	}
+	pos, _ := ecs.GetComponent[position](world, id)
+	pos.X = 10
+	if got, _ := ecs.GetComponent[position](world, id); got.X != 10 {
+		t.Errorf("position X = %f, expected 10", got.X)
+	}
+	ecs.RemoveComponent[position](world, id)
+	if ecs.HasComponent[position](world, id) {
+		t.Errorf("entity %d not expected to have position after removal", id)
+	}
+	world.DestroyEntity(id)
+	if world.IsAlive(id) {
+		t.Errorf("entity %d not expected to be alive", id)
+	}
+	if ecs.AddComponent(world, id, position{}) != nil {
+		t.Errorf("component not expected to be added to destroyed entity")
+	}
+}
+
+func TestQuery(t *testing.T) {
+	world := ecs.NewWorld()
+	var moving []ecs.EntityID
+	for i := 0; i < 6; i++ {
+		id := world.NewEntity()
+		ecs.AddComponent(world, id, position{})
+		if i%2 == 0 {
+			ecs.AddComponent(world, id, velocity{DX: 1})
+			moving = append(moving, id)
+		}
+	}
+	got := ecs.Query(world, reflect.TypeFor[position](), reflect.TypeFor[velocity]())
+	if !slices.Equal(got, moving) {
+		t.Errorf("Query() = %v, expected %v", got, moving)
+	}
+	var visited []ecs.EntityID
+	ecs.Each2(world, func(id ecs.EntityID, pos *position, vel *velocity) {
+		visited = append(visited, id)
+		world.DestroyEntity(id)
+	})
+	if !slices.Equal(visited, moving) {
+		t.Errorf("Each2() visited %v, expected %v", visited, moving)
+	}
+	if world.Len() != 3 {
+		t.Errorf("Len() = %d, expected 3", world.Len())
+	}
+}
+
+func TestSystemOrder(t *testing.T) {
+	world := ecs.NewWorld()
+	var order []string
+	system := func(name string) ecs.ISystem {
+		return ecs.SystemFunc(func(*ecs.World, float64) error {
+			order = append(order, name)
+			return nil
+		})
+	}
+	world.AddSystem(system("render"), 100)
+	world.AddSystem(system("input"), 0)
+	world.AddSystem(system("move"), 10)
+	world.AddSystem(system("collide"), 10)
+	if err := world.Update(1); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"input", "move", "collide", "render"}
+	if !slices.Equal(order, expected) {
+		t.Errorf("systems run in order %v, expected %v", order, expected)
+	}
+}
+
+func TestMovementSystem(t *testing.T) {
+	world := ecs.NewWorld()
+	id := world.NewEntity()
+	ecs.AddComponent(world, id, position{})
+	ecs.AddComponent(world, id, velocity{DX: 2, DY: -1})
+	world.AddSystem(ecs.SystemFunc(func(w *ecs.World, dt float64) error {
+		ecs.Each2(w, func(_ ecs.EntityID, pos *position, vel *velocity) {
+			pos.X += vel.DX * dt
+			pos.Y += vel.DY * dt
+		})
+		return nil
+	}), 0)
+	for i := 0; i < 4; i++ {
+		world.Update(0.5)
+	}
+	if pos, _ := ecs.GetComponent[position](world, id); pos.X != 4 || pos.Y != -2 {
+		t.Errorf("position = (%f, %f), expected (4, -2)", pos.X, pos.Y)
+	}
+}
@@ -1,34 +1,84 @@
 package engine
 
 import (
-	"image"
+	"image/color"
+	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/colorm"
+	"github.com/jrecuero/ebiplay/pkg/tools"
 )
 
+// maxPushOutIterations is the maximum number of overlaps an actor is pushed
+// out of after every move.
+const maxPushOutIterations = 4
+
 type IActor interface {
 	ISolidEntity
 	Draw(*ebiten.Image, *Camera)
-	GetBounds() image.Rectangle
+	GetAlpha() float64
+	GetAnimator() *Animator
+	GetBody() *PhysicsBody
+	GetContacts() []Contact
 	GetDx() float64
 	GetDy() float64
+	GetEffects() *EffectStack
+	GetPivot() (float64, float64)
+	GetRenderLayer() int
+	GetRotation() float64
 	GetScale() float64
+	GetScaleXY() (float64, float64)
+	GetSkew() (float64, float64)
 	GetSpeed() float64
+	GetSpriteBounds() tools.Rect
 	GetSpriteSheet() *SpriteSheet
-	IsSolid() bool
+	GetZIndex() float64
+	MoveAndCollide(float64, float64, *CollisionWorld) []Contact
+	SetAlpha(float64) *Actor
+	SetAnimator(*Animator) *Actor
+	SetBody(*PhysicsBody) *Actor
 	SetDx(float64) *Actor
 	SetDy(float64) *Actor
+	SetPivot(float64, float64) *Actor
+	SetRenderLayer(int) *Actor
+	SetRotation(float64) *Actor
 	SetScale(float64) *Actor
+	SetScaleXY(float64, float64) *Actor
+	SetSkew(float64, float64) *Actor
 	SetSpeed(float64) *Actor
-	Update(...any) error
+	SetZIndex(float64) *Actor
+	Submit(*RenderQueue)
+	Update(*UpdateContext) error
+	UpdatePhysics(float64, *CollisionWorld) []Contact
 }
 
+// Actor structure defines an entity drawn with a sprite sheet.
+//
+// Actor position is the place where the pivot lands in the world. Pivot is
+// given as a fraction of the sprite frame size, where (0, 0) is the top-left
+// corner (default), (0.5, 0.5) the center and (0.5, 1) the feet. Scale,
+// skew and rotation are applied around the pivot.
+//
+// Actor collides with its sprite frame, unless a hitbox is set. Hitbox
+// coordinates are relative to the pivot, and the hitbox follows the actor
+// rotation and scale, so it can fit the visible part of the sprite.
+//
+// Actor moves at a constant speed, unless a physics body is set, and then it
+// moves with the body velocity.
 type Actor struct {
 	*SolidEntity
-	scale         float64
-	speed, dx, dy float64
-	spritesheet   *SpriteSheet
+	alpha          float64
+	pivotX, pivotY float64
+	rotation       float64
+	scaleX, scaleY float64
+	skewX, skewY   float64
+	speed, dx, dy  float64
+	spritesheet    *SpriteSheet
+	animator       *Animator
+	body           *PhysicsBody
+	effects        *EffectStack
+	contacts       []Contact
+	renderLayer    int
+	zIndex         float64
 }
 
 func IsInsideTilemapBoundary(x, y, width, height, tileWidth, tileHeight float64) bool {
@@ -39,48 +89,229 @@ func NewActor(name string, spritesheet *SpriteSheet, x, y float64) *Actor {
 	return &Actor{
 		SolidEntity: NewSolidEntity(name, x, y, 0, 0),
 		spritesheet: spritesheet,
-		scale:       1.0,
-		speed:       2.0,
+		effects:     NewEffectStack(),
+		alpha:       1.0,
+		renderLayer: RenderLayerWorld,
+		scaleX:      1.0,
+		scaleY:      1.0,
+		speed:       120.0,
 		dx:          0.0,
 		dy:          0.0,
 	}
 }
 
-func (a *Actor) ColorDraw(screen *ebiten.Image, camera *Camera) {
-	image := a.GetSpriteSheet().GetFrameFor(a.GetSpriteSheet().frameType)
-	ops := &colorm.DrawImageOptions{}
-	ops.GeoM.Scale(a.GetScale(), a.GetScale())
-	ops.GeoM.Translate(a.GetPos())
-	if camera != nil {
-		ops.GeoM.Translate(float64(camera.X), float64(camera.Y))
+// applyFlip method mirrors the actor sprite around the frame center when the
+// current frame type is declared as a mirror in the sprite sheet, so the
+// mirrored sprite stays in place for any pivot.
+func (a *Actor) applyFlip(geoM *ebiten.GeoM) {
+	spritesheet := a.GetSpriteSheet()
+	flip := spritesheet.GetFlipFor(spritesheet.frameType)
+	ApplyFlip(geoM, flip, float64(spritesheet.Width)/2, float64(spritesheet.Height)/2)
+}
+
+// isInsideBoundary method checks if actor bounds, moved by the given delta,
+// are inside the tilemap boundary.
+func (a *Actor) isInsideBoundary(dx, dy, width, height float64) bool {
+	bounds := a.GetBounds()
+	return IsInsideTilemapBoundary(bounds.X+dx, bounds.Y+dy, width, height, bounds.W, bounds.H)
+}
+
+// isBlocking method returns if the solid collider blocks the rectangle moving
+// by the given delta. One-way colliders only block rectangles moving down
+// that start above them.
+func (a *Actor) isBlocking(other ICollider, rect tools.Rect, dy float64) bool {
+	if oneWay, ok := other.(IOneWay); ok && oneWay.IsOneWay() {
+		return dy > 0 && rect.Bottom() <= other.GetBounds().Y+tools.Epsilon
+	}
+	return true
+}
+
+// isSelf method returns if the collider is the actor itself, or any object
+// embedding it.
+func (a *Actor) isSelf(collider ICollider) bool {
+	return IsSameCollider(a, collider)
+}
+
+// overlapTriggers method appends contacts, without normal, for all triggers
+// in the world overlapping the given shape the actor went through.
+func (a *Actor) overlapTriggers(contacts []Contact, shape tools.IShape, world *CollisionWorld) []Contact {
+	for _, other := range world.QueryRect(shape.GetBounds()) {
+		if other.IsTrigger() && !a.isSelf(other) && CanCollide(a, other) && tools.Overlaps(shape, other.GetShape()) {
+			contacts = appendContact(contacts, Contact{Collider: other})
+		}
+	}
+	return contacts
+}
+
+// pushOut method moves the actor shape, displaced by the given offset, out of
+// the solid colliders and tiles it overlaps, deepest first, using the minimum
+// translation from the separating axis test. Box shapes skip box colliders
+// and tiles, because they have already been swept against them. Start is the
+// actor bounds before the move, and dy the vertical move, used for one-way
+// colliders. It returns the new offset and the contacts found.
+func (a *Actor) pushOut(shape tools.IShape, start tools.Rect, offsetX, offsetY, dy float64, world *CollisionWorld) (float64, float64, []Contact) {
+	swept := isBoxShape(shape)
+	var contacts []Contact
+	for range maxPushOutIterations {
+		moved := shape.Transform(offsetX, offsetY, 0, 1)
+		bounds := moved.GetBounds()
+		var deepest float64
+		var push tools.Vec
+		var found Contact
+		check := func(contact Contact, other tools.IShape, oneWay bool) {
+			normal, depth, ok := tools.Collide(moved, other)
+			// one-way colliders only push the actor up, out of their top side.
+			if oneWay && normal.Y >= 0 {
+				return
+			}
+			if ok && depth > tools.Epsilon && depth > deepest {
+				deepest, push = depth, normal.Scale(depth)
+				contact.NormalX, contact.NormalY = normal.X, normal.Y
+				found = contact
+			}
+		}
+		for _, other := range world.QueryRect(bounds) {
+			if !other.IsSolid() || a.isSelf(other) || !CanCollide(a, other) || !a.isBlocking(other, start, dy) {
+				continue
+			}
+			if otherShape := other.GetShape(); !swept || !isBoxShape(otherShape) {
+				oneWay, ok := other.(IOneWay)
+				check(Contact{Collider: other}, otherShape, ok && oneWay.IsOneWay())
+			}
+		}
+		if grid := world.GetTileGrid(); !swept && grid != nil && grid.GetCollisionLayer()&a.GetCollisionMask() != 0 {
+			for _, tile := range grid.GetSolidTiles(bounds) {
+				tileBounds := grid.GetTileBounds(tile.X, tile.Y)
+				check(Contact{Tile: tile, IsTile: true}, tools.NewBox(tileBounds.X, tileBounds.Y, tileBounds.W, tileBounds.H), false)
+			}
+		}
+		if deepest == 0 {
+			break
+		}
+		offsetX, offsetY = offsetX+push.X, offsetY+push.Y
+		contacts = appendContact(contacts, found)
+	}
+	return offsetX, offsetY, contacts
+}
+
+// sweep method moves the rectangle by the given delta against all solid box
+// colliders in the world the actor can collide with, and solid tiles in the
+// world tile grid, and returns the time of impact, as a fraction of the move,
+// and the contacts for the colliders hit first. Colliders with other shapes
+// are left to pushOut.
+func (a *Actor) sweep(rect tools.Rect, dx, dy float64, world *CollisionWorld) (float64, []Contact) {
+	toi := 1.0
+	var contacts []Contact
+	check := func(contact Contact, bounds tools.Rect) {
+		t, nx, ny, hit := tools.SweepAABB(rect, dx, dy, bounds)
+		if !hit || t > toi+tools.Epsilon {
+			return
+		}
+		if t < toi-tools.Epsilon {
+			toi = t
+			contacts = contacts[:0]
+		}
+		contact.NormalX, contact.NormalY = nx, ny
+		contacts = append(contacts, contact)
+	}
+	region := rect.Union(rect.Translate(dx, dy))
+	for _, other := range world.QueryRect(region) {
+		if !other.IsSolid() || a.isSelf(other) || !CanCollide(a, other) || !a.isBlocking(other, rect, dy) {
+			continue
+		}
+		if !isBoxShape(other.GetShape()) {
+			continue
+		}
+		check(Contact{Collider: other}, other.GetBounds())
+	}
+	if grid := world.GetTileGrid(); grid != nil && grid.GetCollisionLayer()&a.GetCollisionMask() != 0 {
+		for _, tile := range grid.GetSolidTiles(region) {
+			check(Contact{Tile: tile, IsTile: true}, grid.GetTileBounds(tile.X, tile.Y))
+		}
 	}
-	cm := colorm.ColorM{}
-	cm.Translate(1.0, 1.0, 0.0, 0.5)
-	colorm.DrawImage(screen, image, cm, ops)
+	return toi, contacts
 }
 
+// updateBodyInput method sets the physics body horizontal acceleration with
+// the left and right arrow keys, and requests a jump with the up arrow or
+// the space keys.
+func (a *Actor) updateBodyInput(ctx *UpdateContext) {
+	_, ay := a.body.GetAcceleration()
+	if ctx.Input.IsKeyPressed(ebiten.KeyRight) {
+		a.body.SetAcceleration(a.body.GetRunAcceleration(), ay)
+		a.setDirection("right")
+	} else if ctx.Input.IsKeyPressed(ebiten.KeyLeft) {
+		a.body.SetAcceleration(-a.body.GetRunAcceleration(), ay)
+		a.setDirection("left")
+	} else {
+		a.body.SetAcceleration(0, ay)
+	}
+	if ctx.Input.IsKeyJustPressed(ebiten.KeyUp) || ctx.Input.IsKeyJustPressed(ebiten.KeySpace) {
+		a.body.Jump()
+	}
+}
+
+// setDirection method updates the actor facing direction, using the animator
+// when it is available, or the sprite sheet frame type otherwise.
+func (a *Actor) setDirection(direction string) {
+	if a.animator != nil {
+		a.animator.SetParam("direction", direction)
+		return
+	}
+	a.GetSpriteSheet().UpdateFrameType(direction)
+}
+
+// ColorDraw method draws the actor highlighted in yellow on top of any effect
+// in the actor effect stack.
+func (a *Actor) ColorDraw(screen *ebiten.Image, camera *Camera) {
+	highlight := a.effects.Add(NewFlashEffect(color.RGBA{R: 0xff, G: 0xff, A: 0xff}, 0))
+	a.Draw(screen, camera)
+	a.effects.Remove(highlight)
+}
+
+// Draw method draws the actor frame, with all its effects, relative to the
+// camera. The actor is drawn between its previous and current world position
+// using the camera interpolation alpha.
 func (a *Actor) Draw(screen *ebiten.Image, camera *Camera) {
 	image := a.GetSpriteSheet().GetFrameFor(a.GetSpriteSheet().frameType)
-	ops := &ebiten.DrawImageOptions{}
-	ops.GeoM.Scale(a.GetScale(), a.GetScale())
-	ops.GeoM.Translate(a.GetPos())
+	geoM := a.GetTransform()
 	if camera != nil {
-		ops.GeoM.Translate(float64(camera.X), float64(camera.Y))
+		geoM.Translate(GetInterpolationOffset(a, camera.Alpha))
+		camera.Apply(&geoM)
 	}
-	screen.DrawImage(image, ops)
-}
-
-func (a *Actor) GetBounds() image.Rectangle {
-	if a.spritesheet != nil && a.spritesheet.Image != nil {
-		maxX := int(a.x + float64(a.spritesheet.Width)*a.scale)
-		maxY := int(a.y + float64(a.spritesheet.Height)*a.scale)
-		rect := image.Rectangle{
-			Min: image.Pt(int(a.x), int(a.y)),
-			Max: image.Pt(maxX, maxY),
+	alpha := a.alpha
+	if a.animator != nil {
+		if frameType, index, weight, ok := a.animator.GetBlend(); ok {
+			a.effects.Draw(screen, a.GetSpriteSheet().GetFrameAt(frameType, index), geoM, a.alpha*weight)
+			alpha = a.alpha * (1 - weight)
 		}
-		return rect
 	}
-	return image.Rectangle{}
+	a.effects.Draw(screen, image, geoM, alpha)
+}
+
+// GetAlpha method returns the actor opacity, between 0 and 1.
+func (a *Actor) GetAlpha() float64 {
+	return a.alpha
+}
+
+func (a *Actor) GetAnimator() *Animator {
+	return a.animator
+}
+
+func (a *Actor) GetBody() *PhysicsBody {
+	return a.body
+}
+
+// GetBounds method returns the axis aligned rectangle that contains the actor
+// shape.
+func (a *Actor) GetBounds() tools.Rect {
+	return a.GetShape().GetBounds()
+}
+
+// GetContacts method returns the contacts found in the last MoveAndCollide.
+// The slice is only valid until the next move.
+func (a *Actor) GetContacts() []Contact {
+	return a.contacts
 }
 
 func (a *Actor) GetDx() float64 {
@@ -91,27 +322,257 @@ func (a *Actor) GetDy() float64 {
 	return a.dy
 }
 
+func (a *Actor) GetEffects() *EffectStack {
+	return a.effects
+}
+
+// GetPivot method returns the actor pivot as a fraction of the sprite frame
+// size.
+func (a *Actor) GetPivot() (float64, float64) {
+	return a.pivotX, a.pivotY
+}
+
+func (a *Actor) GetRenderLayer() int {
+	return a.renderLayer
+}
+
+// GetRotation method returns the actor rotation in radians.
+func (a *Actor) GetRotation() float64 {
+	return a.rotation
+}
+
+// GetScale method returns the actor horizontal scale, which is the same as
+// the vertical one for uniform scaling.
 func (a *Actor) GetScale() float64 {
-	return a.scale
+	return a.scaleX
+}
+
+func (a *Actor) GetScaleXY() (float64, float64) {
+	return a.scaleX, a.scaleY
+}
+
+func (a *Actor) GetSkew() (float64, float64) {
+	return a.skewX, a.skewY
 }
 
 func (a *Actor) GetSpeed() float64 {
 	return a.speed
 }
 
+// GetShape method returns the actor hitbox in world coordinates, mirrored
+// with the sprite flip and moved by the actor world transform, or the sprite
+// frame after pivot, scale, skew and rotation are applied when no hitbox is
+// set.
+func (a *Actor) GetShape() tools.IShape {
+	if hitbox := a.GetHitbox(); hitbox != nil {
+		geoM := ebiten.GeoM{}
+		if a.spritesheet != nil {
+			// hitbox is relative to the pivot, so the sprite is mirrored
+			// around its center given relative to the pivot.
+			w, h := float64(a.spritesheet.Width), float64(a.spritesheet.Height)
+			flip := a.spritesheet.GetFlipFor(a.spritesheet.frameType)
+			ApplyFlip(&geoM, flip, (0.5-a.pivotX)*w, (0.5-a.pivotY)*h)
+		}
+		geoM.Concat(GetWorldTransform(a))
+		return TransformShape(hitbox, geoM)
+	}
+	if a.spritesheet == nil || a.spritesheet.Image == nil {
+		x, y := GetWorldPos(a)
+		return tools.NewBox(x, y, 0, 0)
+	}
+	geoM := a.GetTransform()
+	w, h := float64(a.spritesheet.Width), float64(a.spritesheet.Height)
+	var points []tools.Vec
+	for _, corner := range []tools.Vec{{X: 0, Y: 0}, {X: w, Y: 0}, {X: w, Y: h}, {X: 0, Y: h}} {
+		px, py := geoM.Apply(corner.X, corner.Y)
+		points = append(points, tools.Vec{X: px, Y: py})
+	}
+	return tools.NewPolygon(points...)
+}
+
+// GetSpriteBounds method returns the axis aligned rectangle that contains the
+// actor sprite frame after pivot, scale, skew and rotation are applied.
+func (a *Actor) GetSpriteBounds() tools.Rect {
+	if a.spritesheet == nil || a.spritesheet.Image == nil {
+		x, y := GetWorldPos(a)
+		return tools.NewRect(x, y, 0, 0)
+	}
+	geoM := a.GetTransform()
+	w, h := float64(a.spritesheet.Width), float64(a.spritesheet.Height)
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, corner := range [][2]float64{{0, 0}, {w, 0}, {0, h}, {w, h}} {
+		x, y := geoM.Apply(corner[0], corner[1])
+		minX, minY = math.Min(minX, x), math.Min(minY, y)
+		maxX, maxY = math.Max(maxX, x), math.Max(maxY, y)
+	}
+	return tools.NewRect(minX, minY, maxX-minX, maxY-minY)
+}
+
 func (a *Actor) GetSpriteSheet() *SpriteSheet {
 	return a.spritesheet
 }
 
-func (a *Actor) IsSolid() bool {
-	return true
+// GetLocalTransform method returns the transform from the actor local
+// coordinates to its parent coordinates: scale, skew, rotation and position,
+// in that order. Children attached to the actor follow this transform.
+func (a *Actor) GetLocalTransform() ebiten.GeoM {
+	geoM := ebiten.GeoM{}
+	geoM.Scale(a.scaleX, a.scaleY)
+	geoM.Skew(a.skewX, a.skewY)
+	geoM.Rotate(a.rotation)
+	geoM.Translate(a.GetPos())
+	return geoM
+}
+
+// GetTransform method returns the geometry matrix that places the actor
+// sprite frame in the world: flip and pivot, followed by the actor world
+// transform. Camera is not included.
+func (a *Actor) GetTransform() ebiten.GeoM {
+	geoM := ebiten.GeoM{}
+	if a.spritesheet != nil {
+		a.applyFlip(&geoM)
+		geoM.Translate(-a.pivotX*float64(a.spritesheet.Width), -a.pivotY*float64(a.spritesheet.Height))
+	}
+	geoM.Concat(GetWorldTransform(a))
+	return geoM
+}
+
+func (a *Actor) GetZIndex() float64 {
+	return a.zIndex
+}
+
+// MoveAndCollide method moves the actor by the given delta against all solid
+// colliders in the collision world, which can be nil to move freely. Only
+// colliders in layers matching the actor mask are checked.
+//
+// Actors with a box shape are swept against boxes and tiles along the
+// horizontal axis first and then along the vertical one, stopping each axis
+// at the first collider hit, so the actor slides along surfaces and can not
+// tunnel through thin colliders at high speed. Other shapes, like circles,
+// capsules or rotated boxes, are pushed out of the overlapped colliders with
+// their real shapes, moving in steps no longer than half the actor size.
+//
+// Triggers do not block the actor, and they are reported as contacts, with
+// no normal, when the actor goes through them.
+//
+// Actor dx and dy are set to the distance really moved, and the contacts
+// found are returned, and kept until the next move. The returned slice is
+// reused by the next move, so callers keeping contacts have to copy them.
+// Contacts are reported to the collision world too, for enter, stay and exit
+// callbacks.
+func (a *Actor) MoveAndCollide(dx, dy float64, world *CollisionWorld) []Contact {
+	a.contacts = a.contacts[:0]
+	if world != nil {
+		shape := a.GetShape()
+		start := shape.GetBounds()
+		var contacts []Contact
+		moveDy := dy
+		if isBoxShape(shape) {
+			rect := start
+			if dx != 0 {
+				toi, contacts := a.sweep(rect, dx, 0, world)
+				dx *= toi
+				rect = rect.Translate(dx, 0)
+				a.contacts = append(a.contacts, contacts...)
+			}
+			if dy != 0 {
+				toi, contacts := a.sweep(rect, 0, dy, world)
+				dy *= toi
+				rect = rect.Translate(0, dy)
+				a.contacts = append(a.contacts, contacts...)
+			}
+			dx, dy, contacts = a.pushOut(shape, start, dx, dy, moveDy, world)
+			for _, contact := range contacts {
+				a.contacts = appendContact(a.contacts, contact)
+			}
+			region := start.Union(rect)
+			a.contacts = a.overlapTriggers(a.contacts, tools.NewBox(region.X, region.Y, region.W, region.H), world)
+		} else {
+			maxStep := math.Max(math.Min(start.W, start.H)/2, 1)
+			steps := max(int(math.Ceil(math.Max(math.Abs(dx), math.Abs(dy))/maxStep)), 1)
+			stepX, stepY := dx/float64(steps), dy/float64(steps)
+			dx, dy = 0, 0
+			for range steps {
+				dx, dy, contacts = a.pushOut(shape, start, dx+stepX, dy+stepY, moveDy, world)
+				for _, contact := range contacts {
+					a.contacts = appendContact(a.contacts, contact)
+				}
+				a.contacts = a.overlapTriggers(a.contacts, shape.Transform(dx, dy, 0, 1), world)
+			}
+		}
+		for _, contact := range a.contacts {
+			if !contact.IsTile {
+				world.AddContact(a, contact.Collider)
+			}
+		}
+	}
+	x, y := a.GetPos()
+	a.SetPos(x+dx, y+dy)
+	a.SetDx(dx)
+	a.SetDy(dy)
+	return a.contacts
+}
+
+// SetAlpha method sets the actor opacity, between 0 and 1.
+func (a *Actor) SetAlpha(alpha float64) *Actor {
+	a.alpha = alpha
+	return a
+}
+
+// SetAnimator method attaches an animation controller to the actor. When an
+// animator is attached, the actor sets "direction" and "speed" parameters
+// instead of updating the sprite sheet frame type directly.
+func (a *Actor) SetAnimator(animator *Animator) *Actor {
+	a.animator = animator
+	return a
+}
+
+// SetBody method attaches a physics body to the actor, or removes it when it
+// is nil.
+func (a *Actor) SetBody(body *PhysicsBody) *Actor {
+	a.body = body
+	return a
+}
+
+// SetPivot method sets the actor pivot as a fraction of the sprite frame
+// size. Actor position, scale, skew and rotation are relative to the pivot.
+func (a *Actor) SetPivot(pivotX, pivotY float64) *Actor {
+	a.pivotX, a.pivotY = pivotX, pivotY
+	return a
+}
+
+// SetRenderLayer method sets the render queue layer the actor is drawn in.
+func (a *Actor) SetRenderLayer(layer int) *Actor {
+	a.renderLayer = layer
+	return a
+}
+
+// SetRotation method sets the actor rotation in radians.
+func (a *Actor) SetRotation(rotation float64) *Actor {
+	a.rotation = rotation
+	return a
 }
 
+// SetScale method sets the same horizontal and vertical scale.
 func (a *Actor) SetScale(scale float64) *Actor {
-	a.scale = scale
+	a.scaleX, a.scaleY = scale, scale
+	return a
+}
+
+func (a *Actor) SetScaleXY(scaleX, scaleY float64) *Actor {
+	a.scaleX, a.scaleY = scaleX, scaleY
+	return a
+}
+
+// SetSkew method sets the horizontal and vertical skew angles in radians.
+func (a *Actor) SetSkew(skewX, skewY float64) *Actor {
+	a.skewX, a.skewY = skewX, skewY
 	return a
 }
 
+// SetSpeed method sets the actor speed in pixels per second. Grid actors use
+// it as the distance for every move instead.
 func (a *Actor) SetSpeed(speed float64) *Actor {
 	a.speed = speed
 	return a
@@ -127,39 +588,100 @@ func (a *Actor) SetDy(dy float64) *Actor {
 	return a
 }
 
-func (a *Actor) Update(args ...any) error {
-	tilemapWidthInPixels := args[0].(float64)
-	tilemapHeightInPixels := args[1].(float64)
-	x, y := a.GetPos()
-	w := float64(a.GetSpriteSheet().Width) * a.GetScale()
-	h := float64(a.GetSpriteSheet().Height) * a.GetScale()
+// SetZIndex method sets the actor draw order inside its render layer.
+// Actors with higher z-index are drawn on top.
+func (a *Actor) SetZIndex(z float64) *Actor {
+	a.zIndex = z
+	return a
+}
+
+// Submit method adds the actor draw to the render queue, using the bottom of
+// the actor sprite for Y-sorting.
+func (a *Actor) Submit(queue *RenderQueue) {
+	queue.Submit(a.renderLayer, a.zIndex, a.GetSpriteBounds().Bottom(), a.Draw)
+}
+
+// Update method moves the actor with the arrow keys, at its speed in pixels
+// per second, inside the context world boundary and against the context
+// collision world, and updates its animation and visual effects. Actors with
+// a physics body run and jump with the arrow keys instead.
+func (a *Actor) Update(ctx *UpdateContext) error {
+	if a.body != nil {
+		a.updateBodyInput(ctx)
+		a.UpdatePhysics(ctx.Delta, ctx.Collision)
+		a.UpdateAnimation(ctx.Delta)
+		a.UpdateEffects(ctx.Delta)
+		return nil
+	}
+	tilemapWidthInPixels, tilemapHeightInPixels := ctx.GetBoundsSize()
+	step := a.GetSpeed() * ctx.Delta
 	a.SetDx(0.0)
 	a.SetDy(0.0)
-	if ebiten.IsKeyPressed(ebiten.KeyRight) {
-		if IsInsideTilemapBoundary(x+a.GetSpeed(), y, tilemapWidthInPixels, tilemapHeightInPixels, w, h) {
-			a.SetDx(a.GetSpeed())
+	if ctx.Input.IsKeyPressed(ebiten.KeyRight) {
+		if a.isInsideBoundary(step, 0, tilemapWidthInPixels, tilemapHeightInPixels) {
+			a.SetDx(step)
 		}
-		a.GetSpriteSheet().UpdateFrameType("right")
-	} else if ebiten.IsKeyPressed(ebiten.KeyLeft) {
-		if IsInsideTilemapBoundary(x-a.GetSpeed(), y, tilemapWidthInPixels, tilemapHeightInPixels, w, h) {
-			a.SetDx(-a.GetSpeed())
+		a.setDirection("right")
+	} else if ctx.Input.IsKeyPressed(ebiten.KeyLeft) {
+		if a.isInsideBoundary(-step, 0, tilemapWidthInPixels, tilemapHeightInPixels) {
+			a.SetDx(-step)
 		}
-		a.GetSpriteSheet().UpdateFrameType("left")
-	} else if ebiten.IsKeyPressed(ebiten.KeyUp) {
-		if IsInsideTilemapBoundary(x, y-a.GetSpeed(), tilemapWidthInPixels, tilemapHeightInPixels, w, h) {
-			a.SetDy(-a.GetSpeed())
+		a.setDirection("left")
+	} else if ctx.Input.IsKeyPressed(ebiten.KeyUp) {
+		if a.isInsideBoundary(0, -step, tilemapWidthInPixels, tilemapHeightInPixels) {
+			a.SetDy(-step)
 		}
-		a.GetSpriteSheet().UpdateFrameType("up")
-	} else if ebiten.IsKeyPressed(ebiten.KeyDown) {
-		if IsInsideTilemapBoundary(x, y+a.GetSpeed(), tilemapWidthInPixels, tilemapHeightInPixels, w, h) {
-			a.SetDy(a.GetSpeed())
+		a.setDirection("up")
+	} else if ctx.Input.IsKeyPressed(ebiten.KeyDown) {
+		if a.isInsideBoundary(0, step, tilemapWidthInPixels, tilemapHeightInPixels) {
+			a.SetDy(step)
 		}
-		a.GetSpriteSheet().UpdateFrameType("down")
+		a.setDirection("down")
 	}
-	a.SetPos(x+a.GetDx(), y+a.GetDy())
+	a.MoveAndCollide(a.GetDx(), a.GetDy(), ctx.Collision)
+	a.UpdateAnimation(ctx.Delta)
+	a.UpdateEffects(ctx.Delta)
 	return nil
 }
 
+// UpdateAnimation method sets gameplay parameters in the animator, evaluates
+// its transitions and advances the sprite sheet frames by the given elapsed
+// time in seconds. It is called by Update, and actors overriding Update
+// should call it every update.
+func (a *Actor) UpdateAnimation(dt float64) {
+	if a.animator != nil {
+		a.animator.SetParam("speed", math.Hypot(a.dx, a.dy))
+		a.animator.Update(dt)
+	}
+	if a.spritesheet != nil {
+		a.spritesheet.Update(dt)
+	}
+}
+
+// UpdateEffects method advances all visual effects for the actor by the
+// given elapsed time in seconds, removing the expired ones. It is called by
+// Update, and actors overriding Update should call it every update.
+func (a *Actor) UpdateEffects(dt float64) {
+	a.effects.Update(dt)
+}
+
+// UpdatePhysics method integrates the actor physics body for the given
+// elapsed time in seconds, moves the actor with the body velocity against the
+// collision world, which can be nil, and updates the body with the contacts
+// found. It does nothing for actors without a physics body. The returned
+// contacts are only valid until the next move.
+func (a *Actor) UpdatePhysics(dt float64, world *CollisionWorld) []Contact {
+	if a.body == nil {
+		return nil
+	}
+	a.body.integrate(dt)
+	vx, vy := a.body.GetVelocity()
+	contacts := a.MoveAndCollide(vx*dt, vy*dt, world)
+	a.body.resolve(contacts)
+	return contacts
+}
+
 var _ IActor = (*Actor)(nil)
 var _ IDrawable = (*Actor)(nil)
 var _ IUpdatable = (*Actor)(nil)
+var _ IRenderable = (*Actor)(nil)
@@ -0,0 +1,342 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// AnyState is the state name used to declare transitions that can be taken
+// from any animation state.
+const AnyState = "*"
+
+// AnimationCondition type defines a function that checks animator parameters
+// to decide if a transition can be taken.
+type AnimationCondition func(*Animator) bool
+
+// AnimationTransition structure defines a transition between two animation
+// states.
+//
+// A transition is taken when all conditions are true, the trigger (if any)
+// has been set and the current animation has played at least the exit time
+// cycles. Blend is the time, in seconds, the previous animation is
+// crossfaded with the new one.
+type AnimationTransition struct {
+	to         string
+	conditions []AnimationCondition
+	trigger    string
+	exitTime   float64
+	blend      float64
+}
+
+// AnimationState structure defines a state in the animator. The frame type
+// can contain parameter placeholders like "attack/{direction}" that are
+// resolved with animator string parameters.
+type AnimationState struct {
+	name        string
+	frameType   string
+	transitions []*AnimationTransition
+}
+
+// Animator structure defines a declarative animation controller. It selects
+// the sprite sheet frame type from gameplay parameters using states and
+// transitions. It is not an entity, so it only has a name, without taking an
+// entity ID.
+type Animator struct {
+	name           string
+	spritesheet    *SpriteSheet
+	states         map[string]*AnimationState
+	anyTransitions []*AnimationTransition
+	current        *AnimationState
+	params         map[string]any
+	triggers       map[string]bool
+	blendFrom      string
+	blendIndex     int
+	blendDuration  float64
+	blendLeft      float64
+}
+
+// NewAnimator function creates a new Animator instance for the given sprite
+// sheet.
+func NewAnimator(name string, spritesheet *SpriteSheet) *Animator {
+	return &Animator{
+		name:        name,
+		spritesheet: spritesheet,
+		states:      make(map[string]*AnimationState),
+		params:      make(map[string]any),
+		triggers:    make(map[string]bool),
+	}
+}
+
+// IfEquals function returns a condition that checks if the parameter is equal
+// to the given value.
+func IfEquals(name string, value any) AnimationCondition {
+	return func(a *Animator) bool {
+		return a.params[name] == value
+	}
+}
+
+// IfFalse function returns a condition that checks if the boolean parameter
+// is false or not set.
+func IfFalse(name string) AnimationCondition {
+	return func(a *Animator) bool {
+		return !a.GetBool(name)
+	}
+}
+
+// IfGreater function returns a condition that checks if the float parameter is
+// greater than the given value.
+func IfGreater(name string, value float64) AnimationCondition {
+	return func(a *Animator) bool {
+		return a.GetFloat(name) > value
+	}
+}
+
+// IfLess function returns a condition that checks if the float parameter is
+// less than the given value.
+func IfLess(name string, value float64) AnimationCondition {
+	return func(a *Animator) bool {
+		return a.GetFloat(name) < value
+	}
+}
+
+// IfTrue function returns a condition that checks if the boolean parameter is
+// true.
+func IfTrue(name string) AnimationCondition {
+	return func(a *Animator) bool {
+		return a.GetBool(name)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// AnimationState public methods
+// -----------------------------------------------------------------------------
+
+func (s *AnimationState) GetFrameType() string {
+	return s.frameType
+}
+
+func (s *AnimationState) GetName() string {
+	return s.name
+}
+
+// -----------------------------------------------------------------------------
+// AnimationTransition public methods
+// -----------------------------------------------------------------------------
+
+// SetBlend method sets the time, in seconds, the previous animation is
+// crossfaded with the new one.
+func (t *AnimationTransition) SetBlend(blend float64) *AnimationTransition {
+	t.blend = blend
+	return t
+}
+
+// SetExitTime method sets the number of cycles, as a fraction, the current
+// animation has to play before the transition can be taken.
+func (t *AnimationTransition) SetExitTime(exitTime float64) *AnimationTransition {
+	t.exitTime = exitTime
+	return t
+}
+
+// SetTrigger method sets the trigger required by the transition. The trigger
+// is consumed when the transition is taken.
+func (t *AnimationTransition) SetTrigger(trigger string) *AnimationTransition {
+	t.trigger = trigger
+	return t
+}
+
+// -----------------------------------------------------------------------------
+// Animator private methods
+// -----------------------------------------------------------------------------
+
+// canTransition method checks if the given transition can be taken from the
+// current state.
+func (a *Animator) canTransition(transition *AnimationTransition) bool {
+	if transition.trigger != "" && !a.triggers[transition.trigger] {
+		return false
+	}
+	if transition.exitTime > 0 && a.spritesheet.GetFrameProgress() < transition.exitTime {
+		return false
+	}
+	for _, condition := range transition.conditions {
+		if !condition(a) {
+			return false
+		}
+	}
+	return true
+}
+
+// enterState method moves the animator to the given state, starting a blend
+// with the previous animation if required.
+func (a *Animator) enterState(state *AnimationState, blend float64) {
+	if a.current != nil && blend > 0 {
+		a.blendFrom = a.spritesheet.GetFrameType()
+		a.blendIndex = a.spritesheet.GetFrameIndex()
+		a.blendDuration = blend
+		a.blendLeft = blend
+	}
+	a.current = state
+	a.spritesheet.UpdateFrameType(a.resolveFrameType(state.frameType))
+}
+
+// resolveFrameType method replaces parameter placeholders in the given frame
+// type with string parameter values.
+func (a *Animator) resolveFrameType(frameType string) string {
+	if !strings.Contains(frameType, "{") {
+		return frameType
+	}
+	for name, value := range a.params {
+		if text, ok := value.(string); ok {
+			frameType = strings.ReplaceAll(frameType, "{"+name+"}", text)
+		}
+	}
+	return frameType
+}
+
+// takeTransition method enters the state for the first transition in the
+// list whose conditions are met, and returns if any transition was taken.
+func (a *Animator) takeTransition(transitions []*AnimationTransition) bool {
+	for _, transition := range transitions {
+		if transition.to == a.current.name || !a.canTransition(transition) {
+			continue
+		}
+		state, ok := a.states[transition.to]
+		if !ok {
+			log.Fatalf("unknown animation state %s", transition.to)
+		}
+		if transition.trigger != "" {
+			a.ResetTrigger(transition.trigger)
+		}
+		a.enterState(state, transition.blend)
+		return true
+	}
+	return false
+}
+
+// -----------------------------------------------------------------------------
+// Animator public methods
+// -----------------------------------------------------------------------------
+
+// AddState method adds a new state to the animator. The first state added
+// becomes the current one.
+func (a *Animator) AddState(name string, frameType string) *AnimationState {
+	state := &AnimationState{
+		name:      name,
+		frameType: frameType,
+	}
+	a.states[name] = state
+	if a.current == nil {
+		a.current = state
+	}
+	return state
+}
+
+// AddTransition method adds a new transition between two states. AnyState can
+// be used as source state for transitions to be checked from every state.
+// Source state has to be already added, while target state can be added
+// later, but it has to exist when the transition is taken.
+func (a *Animator) AddTransition(from string, to string, conditions ...AnimationCondition) *AnimationTransition {
+	transition := &AnimationTransition{
+		to:         to,
+		conditions: conditions,
+	}
+	if from == AnyState {
+		a.anyTransitions = append(a.anyTransitions, transition)
+		return transition
+	}
+	state, ok := a.states[from]
+	if !ok {
+		log.Fatalf("unknown animation state %s", from)
+	}
+	state.transitions = append(state.transitions, transition)
+	return transition
+}
+
+// GetBlend method returns the previous animation frame to be crossfaded and
+// its weight, if there is a blend in progress.
+func (a *Animator) GetBlend() (string, int, float64, bool) {
+	if a.blendLeft <= 0 {
+		return "", 0, 0, false
+	}
+	weight := a.blendLeft / a.blendDuration
+	return a.blendFrom, a.blendIndex, weight, true
+}
+
+// GetBool method returns the boolean parameter for the given name.
+func (a *Animator) GetBool(name string) bool {
+	value, _ := a.params[name].(bool)
+	return value
+}
+
+// GetFloat method returns the float parameter for the given name.
+func (a *Animator) GetFloat(name string) float64 {
+	value, _ := a.params[name].(float64)
+	return value
+}
+
+func (a *Animator) GetName() string {
+	return a.name
+}
+
+func (a *Animator) GetSpriteSheet() *SpriteSheet {
+	return a.spritesheet
+}
+
+// GetState method returns the name of the current state.
+func (a *Animator) GetState() string {
+	if a.current == nil {
+		return ""
+	}
+	return a.current.name
+}
+
+// GetString method returns the string parameter for the given name.
+func (a *Animator) GetString(name string) string {
+	value, _ := a.params[name].(string)
+	return value
+}
+
+// ResetTrigger method clears the given trigger.
+func (a *Animator) ResetTrigger(name string) {
+	delete(a.triggers, name)
+}
+
+// SetParam method sets the value for the given parameter. Supported values
+// are bool, float64 and string.
+func (a *Animator) SetParam(name string, value any) *Animator {
+	a.params[name] = value
+	return a
+}
+
+// SetState method forces the animator to the given state.
+func (a *Animator) SetState(name string) error {
+	state, ok := a.states[name]
+	if !ok {
+		return fmt.Errorf("unknown animation state %s", name)
+	}
+	a.enterState(state, 0)
+	return nil
+}
+
+// SetTrigger method sets the given trigger, which remains set until a
+// transition consumes it or it is reset.
+func (a *Animator) SetTrigger(name string) *Animator {
+	a.triggers[name] = true
+	return a
+}
+
+// Update method advances any blend in progress by the given elapsed time in
+// seconds, evaluates transitions for the current state and updates the
+// sprite sheet frame type. It should be called once every update.
+func (a *Animator) Update(dt float64) {
+	if a.current == nil {
+		return
+	}
+	if a.blendLeft > 0 {
+		a.blendLeft = max(a.blendLeft-dt, 0)
+	}
+	if a.takeTransition(a.current.transitions) || a.takeTransition(a.anyTransitions) {
+		return
+	}
+	a.spritesheet.UpdateFrameType(a.resolveFrameType(a.current.frameType))
+}
@@ -1,9 +1,8 @@
 package engine
 
 import (
-	"fmt"
+	"log"
 	"math/rand"
-	"sync/atomic"
 	"time"
 )
 
@@ -21,10 +20,15 @@ var (
 	//
 	// It is initialized with a time-based seed (`time.Now().UnixNano()`) to ensure
 	// that the random numbers generated are different across program executions.
-	// This random number generator is used in the `generateUniqueID` function to
-	// add a random component to the generated ID, improving uniqueness and reducing
+	// This random number generator is used in the `TimeIDGenerator` to add a
+	// random component to the generated ID, improving uniqueness and reducing
 	// the likelihood of collisions.
 	rng *rand.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	// idGenerator is the package-level generator used for all new Base
+	// instances. It defaults to the time-based generator and can be replaced
+	// with SetIDGenerator.
+	idGenerator IIDGenerator = NewTimeIDGenerator()
 )
 
 // IBase defines the interface for objects with basic identification capabilities.
@@ -63,39 +67,48 @@ type IBase interface {
 // The id field is set upon creation and is immutable, while name and tag
 // can be modified using their respective setter methods.
 type Base struct {
-	id   string
-	name string
-	tag  string
+	id      string
+	name    string
+	tag     string
+	indexed int
 }
 
-// generateUniqueID creates a unique identifier string.
-//
-// The generated ID combines three components:
-// 1. A timestamp (in milliseconds) to ensure uniqueness across time.
-// 2. An atomic counter to guarantee uniqueness even within the same millisecond.
-// 3. A random number for additional entropy.
-//
-// The resulting format is: "timestamp-counter-randomNum"
-// where timestamp is in milliseconds, counter is an incrementing integer,
-// and randomNum is a three-digit random number.
-//
-// This function is safe for concurrent use.
+// idIndexed interface is implemented by objects that track how many indexes,
+// like registries and collision worlds, hold them by ID. Base implements it,
+// so every object embedding a Base does.
+type idIndexed interface {
+	addIDIndex(int)
+}
+
+// addIDIndex method adds the given delta to the number of indexes holding the
+// Base instance by ID.
+func (b *Base) addIDIndex(delta int) {
+	b.indexed += delta
+}
+
+// trackIDIndex function updates the index count for objects implementing the
+// idIndexed interface.
+func trackIDIndex(obj any, delta int) {
+	if indexed, ok := obj.(idIndexed); ok {
+		indexed.addIDIndex(delta)
+	}
+}
+
+// generateUniqueID creates a unique identifier string using the package ID
+// generator.
 //
 // Returns:
-//   - string: A unique identifier in the format "timestamp-counter-randomNum".
+//   - string: A unique identifier in the format defined by the generator.
 func generateUniqueID() string {
-	timestamp := time.Now().UnixNano() / int64(time.Millisecond)
-	randomNum := rng.Intn(1000)
-	count := atomic.AddUint64(&counter, 1)
-	return fmt.Sprintf("%d-%d-%03d", timestamp, count, randomNum)
+	return idGenerator.NextID()
 }
 
 // NewBase creates and returns a new instance of the Base struct.
 //
 // This function initializes a Base object with a given name, automatically
 // generating a unique ID and setting an initial empty tag. It uses the
-// generateUniqueID function to create the ID, ensuring uniqueness across
-// all Base instances.
+// package ID generator to create the ID, ensuring uniqueness across all Base
+// instances.
 //
 // Parameters:
 //   - name string: The name to assign to the new Base instance. This should be
@@ -117,6 +130,28 @@ func NewBase(name string) *Base {
 	}
 }
 
+// NewBaseWithID creates and returns a new instance of the Base struct with
+// the given ID, instead of a generated one.
+//
+// This function is intended to restore objects when loading a saved state.
+// The ID generator is notified about the restored ID when it implements the
+// IIDRestorer interface.
+//
+// Parameters:
+//   - name string: The name to assign to the new Base instance.
+//   - id string: The unique identifier to assign to the new Base instance.
+//
+// Returns:
+//   - *Base: A pointer to the newly created Base instance.
+func NewBaseWithID(name string, id string) *Base {
+	base := &Base{
+		name: name,
+		tag:  "", // Initialize with an empty tag
+	}
+	base.RestoreID(id)
+	return base
+}
+
 // GetID returns the unique identifier of the Base instance.
 //
 // This method provides read-only access to the `id` field, which is intended
@@ -150,6 +185,27 @@ func (b *Base) GetTag() string {
 	return b.tag
 }
 
+// RestoreID replaces the unique identifier of the Base instance.
+//
+// The `id` field is immutable during normal use. This method is intended only
+// to restore objects created with constructors, like NewActor, when loading a
+// saved state. It must be called before the instance is added to a registry
+// or a collision world, because they index objects by ID; calling it on an
+// instance already added is a fatal error. The ID generator is notified about
+// the restored ID when it implements the IIDRestorer interface.
+//
+// Parameters:
+//   - id (string): The unique identifier to restore.
+func (b *Base) RestoreID(id string) {
+	if b.indexed > 0 {
+		log.Fatalf("can not restore ID %s in registered object %s", id, b.id)
+	}
+	b.id = id
+	if restorer, ok := idGenerator.(IIDRestorer); ok {
+		restorer.RestoreID(id)
+	}
+}
+
 // SetName updates the name of the Base instance.
 //
 // This method allows you to modify the `name` field, which represents
@@ -1,32 +1,636 @@
 package engine
 
 import (
+	"image/color"
 	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/jrecuero/ebiplay/pkg/tools"
+)
+
+// Default camera shake values, see Camera.SetShake.
+const (
+	DefaultShakeOffset    = 16
+	DefaultShakeAngle     = 0.05
+	DefaultShakeFrequency = 20
+	DefaultTraumaDecay    = 1
 )
 
+// FollowMode type defines how the camera moves towards its targets.
+type FollowMode int
+
+const (
+	// FollowSnap mode centers the targets immediately.
+	FollowSnap FollowMode = iota
+	// FollowLerp mode moves a fraction of the distance every second, given
+	// by the smoothing rate.
+	FollowLerp
+	// FollowDamped mode moves like a critically damped spring, reaching the
+	// targets in about the smoothing time in seconds without overshooting.
+	FollowDamped
+)
+
+// Camera structure defines the view used to draw the world. X and Y are the
+// view offset, the world position at the top-left corner of the screen with
+// the sign changed. Zoom and rotation are applied around the screen center.
+//
+// Alpha is the interpolation alpha for the current draw, set by the game
+// from the simulation clock. It is one by default, which draws the current
+// state without any interpolation.
+//
+// Drawables call Apply to move their world transform to the screen, so they
+// do not have to know the camera math.
+//
+// The camera can follow one or more targets, calling Update every simulation
+// step, with smoothing, a dead zone where targets move without moving the
+// camera, look-ahead in the movement direction and zoom to frame all
+// targets. The view is interpolated between steps with Alpha, and it is kept
+// inside the limits, when they are set. FollowTo and Constrain move the
+// camera immediately instead.
+//
+// Effects are updated with the camera too: trauma-based screen shake, full
+// screen color flashes and fades drawn with DrawOverlay, and scripted pans
+// that move the camera away from its targets, for hits and cutscenes.
 type Camera struct {
-	X, Y          float64
-	Width, Height float64
+	X, Y                          float64
+	Width, Height                 float64
+	Zoom                          float64
+	Rotation                      float64
+	Alpha                         float64
+	prevX, prevY                  float64
+	targets                       []IEntity
+	following                     bool
+	followMode                    FollowMode
+	smoothing                     float64
+	velocityX, velocityY          float64
+	deadZoneWidth, deadZoneHeight float64
+	lookAhead, lookAheadRate      float64
+	lookX, lookY                  float64
+	framingMargin                 float64
+	minZoom, maxZoom              float64
+	limitWidth, limitHeight       float64
+	pan                           *CameraPan
+	trauma, traumaDecay           float64
+	shakeOffset, shakeAngle       float64
+	shakeFrequency, shakeTime     float64
+	shakeX, shakeY, shakeRotation float64
+	overlay                       *Tween
+	overlayColor                  color.Color
+	overlayAlpha                  float64
 }
 
 func NewCamera(x, y, width, height float64) *Camera {
 	return &Camera{
-		X:      x,
-		Y:      y,
-		Width:  width,
-		Height: height,
+		X:              x,
+		Y:              y,
+		Width:          width,
+		Height:         height,
+		Zoom:           1,
+		Alpha:          1,
+		prevX:          x,
+		prevY:          y,
+		traumaDecay:    DefaultTraumaDecay,
+		shakeOffset:    DefaultShakeOffset,
+		shakeAngle:     DefaultShakeAngle,
+		shakeFrequency: DefaultShakeFrequency,
 	}
 }
 
-func (c *Camera) FollowTo(x, y float64) {
+// -----------------------------------------------------------------------------
+// Camera private methods
+// -----------------------------------------------------------------------------
+
+// applyDeadZone function returns the camera center for one axis that keeps
+// the target inside the dead zone with the given half size.
+func applyDeadZone(center, target, halfSize float64) float64 {
+	if target > center+halfSize {
+		return target - halfSize
+	}
+	if target < center-halfSize {
+		return target + halfSize
+	}
+	return center
+}
+
+// clamp method returns the given camera center moved to keep the view inside
+// a map with the given size, taking zoom into account. Maps smaller than the
+// view are centered.
+func (c *Camera) clamp(centerX, centerY, width, height float64) (float64, float64) {
+	halfWidth, halfHeight := c.Width/(2*c.Zoom), c.Height/(2*c.Zoom)
+	if width < 2*halfWidth {
+		centerX = width / 2
+	} else {
+		centerX = math.Max(halfWidth, math.Min(centerX, width-halfWidth))
+	}
+	if height < 2*halfHeight {
+		centerY = height / 2
+	} else {
+		centerY = math.Max(halfHeight, math.Min(centerY, height-halfHeight))
+	}
+	return centerX, centerY
+}
+
+// getFollowGoal method returns the camera center for the targets, after
+// framing zoom, look-ahead, dead zone and limits are applied. Without
+// targets the camera stays where it is.
+func (c *Camera) getFollowGoal(dt float64) (float64, float64) {
+	if len(c.targets) == 0 {
+		return c.GetCenter()
+	}
+	targetX, targetY, frameWidth, frameHeight, vx, vy := c.getFrame(dt)
+	if c.minZoom > 0 && len(c.targets) > 1 {
+		zoom := math.Min(c.Width/(frameWidth+2*c.framingMargin), c.Height/(frameHeight+2*c.framingMargin))
+		zoom = math.Max(c.minZoom, math.Min(zoom, c.maxZoom))
+		if c.following && c.followMode != FollowSnap && c.smoothing > 0 {
+			zoom = tools.SmoothLerp(c.Zoom, zoom, 1/c.smoothing, dt)
+		}
+		c.Zoom = zoom
+	}
+	if c.lookAhead > 0 {
+		direction := tools.Vec{X: vx, Y: vy}.Normalize()
+		c.lookX = tools.SmoothLerp(c.lookX, direction.X*c.lookAhead, c.lookAheadRate, dt)
+		c.lookY = tools.SmoothLerp(c.lookY, direction.Y*c.lookAhead, c.lookAheadRate, dt)
+	}
+	centerX, centerY := c.GetCenter()
+	goalX := applyDeadZone(centerX, targetX+c.lookX, c.deadZoneWidth/(2*c.Zoom))
+	goalY := applyDeadZone(centerY, targetY+c.lookY, c.deadZoneHeight/(2*c.Zoom))
+	return c.limit(goalX, goalY)
+}
+
+// getFrame method returns the center of the box containing all targets, its
+// size, and the velocity of the targets average position in pixels per
+// second, which does not jump when targets swap places in the box.
+func (c *Camera) getFrame(dt float64) (float64, float64, float64, float64, float64, float64) {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	var moveX, moveY float64
+	for _, target := range c.targets {
+		x, y := target.GetPos()
+		prevX, prevY := target.GetPrevPos()
+		minX, minY = math.Min(minX, x), math.Min(minY, y)
+		maxX, maxY = math.Max(maxX, x), math.Max(maxY, y)
+		moveX, moveY = moveX+x-prevX, moveY+y-prevY
+	}
+	var vx, vy float64
+	if dt > 0 {
+		count := float64(len(c.targets))
+		vx, vy = moveX/count/dt, moveY/count/dt
+	}
+	return (minX + maxX) / 2, (minY + maxY) / 2, maxX - minX, maxY - minY, vx, vy
+}
+
+// limit method returns the given camera center kept inside the limits, when
+// they are set.
+func (c *Camera) limit(centerX, centerY float64) (float64, float64) {
+	if c.limitWidth > 0 && c.limitHeight > 0 {
+		return c.clamp(centerX, centerY, c.limitWidth, c.limitHeight)
+	}
+	return centerX, centerY
+}
+
+// setCenter method moves the camera to center the given world position,
+// without changing the previous position used for interpolation.
+func (c *Camera) setCenter(x, y float64) {
 	c.X = -x + c.Width/2
 	c.Y = -y + c.Height/2
 }
 
+// shakeNoise function returns a smooth pseudo random value between minus one
+// and one for the given time, different for every seed.
+func shakeNoise(seed, t float64) float64 {
+	return (math.Sin(t+seed) + 0.5*math.Sin(2.17*t+1.3*seed) + 0.25*math.Sin(4.73*t+2.9*seed)) / 1.75
+}
+
+// updateFollow method moves the camera towards its targets.
+func (c *Camera) updateFollow(dt float64) {
+	if len(c.targets) == 0 {
+		return
+	}
+	goalX, goalY := c.getFollowGoal(dt)
+	if !c.following {
+		c.following = true
+		c.velocityX, c.velocityY = 0, 0
+		c.FollowTo(goalX, goalY)
+		return
+	}
+	centerX, centerY := c.GetCenter()
+	switch c.followMode {
+	case FollowLerp:
+		centerX = tools.SmoothLerp(centerX, goalX, c.smoothing, dt)
+		centerY = tools.SmoothLerp(centerY, goalY, c.smoothing, dt)
+	case FollowDamped:
+		centerX = tools.SmoothDamp(centerX, goalX, &c.velocityX, c.smoothing, dt)
+		centerY = tools.SmoothDamp(centerY, goalY, &c.velocityY, c.smoothing, dt)
+	default:
+		centerX, centerY = goalX, goalY
+	}
+	c.setCenter(c.limit(centerX, centerY))
+}
+
+// updatePan method moves the camera along the running pan. Finished pans
+// keep the camera on their destination until other pan starts or the camera
+// returns to follow its targets.
+func (c *Camera) updatePan(dt float64) {
+	pan := c.pan
+	pan.Update(dt)
+	// the pan can be replaced by its own completion callback.
+	if pan != c.pan || !pan.IsFinished() {
+		return
+	}
+	if pan.release {
+		c.pan = nil
+		c.following = true
+		c.velocityX, c.velocityY = 0, 0
+		return
+	}
+	c.setCenter(pan.destination(dt))
+}
+
+// updateShake method decays the trauma and computes the shake offset and
+// rotation, which grow with the square of the trauma.
+func (c *Camera) updateShake(dt float64) {
+	c.trauma = math.Max(0, c.trauma-c.traumaDecay*dt)
+	if c.trauma == 0 {
+		c.shakeX, c.shakeY, c.shakeRotation = 0, 0, 0
+		return
+	}
+	c.shakeTime += dt
+	amount := c.trauma * c.trauma
+	t := c.shakeTime * c.shakeFrequency
+	c.shakeX = amount * c.shakeOffset * shakeNoise(0, t)
+	c.shakeY = amount * c.shakeOffset * shakeNoise(10, t)
+	c.shakeRotation = amount * c.shakeAngle * shakeNoise(20, t)
+}
+
+// -----------------------------------------------------------------------------
+// Camera public methods
+// -----------------------------------------------------------------------------
+
+// AddTarget method adds an entity to the targets the camera follows.
+func (c *Camera) AddTarget(target IEntity) *Camera {
+	c.targets = append(c.targets, target)
+	return c
+}
+
+// AddTrauma method adds the given amount to the camera trauma, clamped
+// between zero and one, which shakes the camera until it decays. Small hits
+// can add around 0.2 and big explosions 0.6 or more.
+func (c *Camera) AddTrauma(amount float64) *Camera {
+	c.trauma = math.Max(0, math.Min(c.trauma+amount, 1))
+	return c
+}
+
+// Apply method concatenates the camera view matrix to the given world
+// transform. It does nothing for a nil camera, so drawables can be drawn
+// without camera.
+func (c *Camera) Apply(geoM *ebiten.GeoM) {
+	if c == nil {
+		return
+	}
+	view := c.GetViewMatrix()
+	geoM.Concat(view)
+}
+
+// ClearTargets method removes all targets the camera follows.
+func (c *Camera) ClearTargets() *Camera {
+	c.targets = nil
+	return c
+}
+
+// Constrain method keeps the view inside the tilemap immediately, taking
+// zoom into account. Tilemaps smaller than the view are centered.
 func (c *Camera) Constrain(tilemapWidthInPixels, tilemapHeightInPixels float64) {
-	c.X = math.Min(c.X, 0)
-	c.Y = math.Min(c.Y, 0)
+	centerX, centerY := c.GetCenter()
+	c.FollowTo(c.clamp(centerX, centerY, tilemapWidthInPixels, tilemapHeightInPixels))
+}
+
+// DrawOverlay method draws the flash or fade color over the whole screen.
+// It has to be called after the world has been drawn.
+func (c *Camera) DrawOverlay(screen *ebiten.Image) {
+	if c.overlayColor == nil || c.overlayAlpha <= 0 {
+		return
+	}
+	amount := math.Min(c.overlayAlpha, 1)
+	r, g, b, a := c.overlayColor.RGBA()
+	overlay := color.RGBA64{
+		R: uint16(float64(r) * amount),
+		G: uint16(float64(g) * amount),
+		B: uint16(float64(b) * amount),
+		A: uint16(float64(a) * amount),
+	}
+	bounds := screen.Bounds()
+	vector.DrawFilledRect(screen, float32(bounds.Min.X), float32(bounds.Min.Y), float32(bounds.Dx()), float32(bounds.Dy()), overlay, false)
+}
+
+// Fade method changes the overlay opacity from its current value to the
+// given one over the given time in seconds. Fading to one covers the screen
+// with the color, and fading to zero shows the world again. The returned
+// tween can be used to set the easing or a completion callback.
+func (c *Camera) Fade(clr color.Color, alpha, duration float64) *Tween {
+	c.overlayColor = clr
+	c.overlay = NewTweenTo(func() float64 { return c.overlayAlpha }, func(v float64) { c.overlayAlpha = v }, alpha, duration)
+	return c.overlay
+}
+
+// Flash method covers the screen with the given color, which fades out over
+// the given time in seconds. The returned tween can be used to set the
+// easing or a completion callback.
+func (c *Camera) Flash(clr color.Color, duration float64) *Tween {
+	c.overlayColor = clr
+	c.overlayAlpha = 1
+	c.overlay = NewTween(1, 0, duration, func(v float64) { c.overlayAlpha = v })
+	return c.overlay
+}
+
+// FollowTo method centers the view on the given world position immediately,
+// without interpolation.
+func (c *Camera) FollowTo(x, y float64) {
+	c.setCenter(x, y)
+	c.prevX, c.prevY = c.X, c.Y
+}
+
+// GetCenter method returns the world position at the screen center.
+func (c *Camera) GetCenter() (float64, float64) {
+	return -c.X + c.Width/2, -c.Y + c.Height/2
+}
+
+// GetOverlay method returns the flash or fade color and its opacity.
+func (c *Camera) GetOverlay() (color.Color, float64) {
+	return c.overlayColor, c.overlayAlpha
+}
+
+func (c *Camera) GetTargets() []IEntity {
+	return c.targets
+}
 
-	c.X = math.Max(c.X, c.Width-tilemapWidthInPixels)
-	c.Y = math.Max(c.Y, c.Height-tilemapHeightInPixels)
+func (c *Camera) GetTrauma() float64 {
+	return c.trauma
 }
+
+// GetViewMatrix method returns the transform from world to screen
+// coordinates: offset, interpolated between the previous and the current
+// step, and then zoom and rotation around the screen center, with the shake
+// offset in screen pixels.
+func (c *Camera) GetViewMatrix() ebiten.GeoM {
+	geoM := ebiten.GeoM{}
+	x, y := tools.Lerp(c.prevX, c.X, c.Alpha), tools.Lerp(c.prevY, c.Y, c.Alpha)
+	geoM.Translate(x-c.Width/2, y-c.Height/2)
+	geoM.Rotate(-c.Rotation - c.shakeRotation)
+	geoM.Scale(c.Zoom, c.Zoom)
+	geoM.Translate(c.Width/2+c.shakeX, c.Height/2+c.shakeY)
+	return geoM
+}
+
+// IsPanning method returns if a pan controls the camera instead of its
+// targets, including finished pans holding the camera on their destination.
+func (c *Camera) IsPanning() bool {
+	return c.pan != nil
+}
+
+// PanTo method starts moving the camera center to the given world position
+// over the given time in seconds. The camera stays there, even after the pan
+// has finished, until ReturnToFollow or StopPan are called.
+func (c *Camera) PanTo(x, y, duration float64) *CameraPan {
+	c.pan = newCameraPan(c, func(float64) (float64, float64) { return c.limit(x, y) }, duration, false)
+	return c.pan
+}
+
+// PanToEntity method starts moving the camera center to the given entity
+// over the given time in seconds. The camera keeps the entity centered, even
+// after the pan has finished, until ReturnToFollow or StopPan are called.
+func (c *Camera) PanToEntity(entity IEntity, duration float64) *CameraPan {
+	c.pan = newCameraPan(c, func(float64) (float64, float64) { return c.limit(entity.GetPos()) }, duration, false)
+	return c.pan
+}
+
+// RemoveTarget method removes an entity from the targets the camera follows.
+func (c *Camera) RemoveTarget(target IEntity) *Camera {
+	for i, t := range c.targets {
+		if t.GetID() == target.GetID() {
+			c.targets = append(c.targets[:i:i], c.targets[i+1:]...)
+			break
+		}
+	}
+	return c
+}
+
+// ReturnToFollow method starts moving the camera back to its targets over
+// the given time in seconds, and then follows them again.
+func (c *Camera) ReturnToFollow(duration float64) *CameraPan {
+	c.pan = newCameraPan(c, c.getFollowGoal, duration, true)
+	return c.pan
+}
+
+// ScreenToWorld method returns the world position for the given screen
+// position, like the cursor position.
+func (c *Camera) ScreenToWorld(x, y float64) (float64, float64) {
+	view := c.GetViewMatrix()
+	if !view.IsInvertible() {
+		return x, y
+	}
+	view.Invert()
+	return view.Apply(x, y)
+}
+
+// SetDeadZone method sets the size, in screen pixels, of the rectangle
+// around the screen center where targets move without moving the camera.
+func (c *Camera) SetDeadZone(width, height float64) *Camera {
+	c.deadZoneWidth, c.deadZoneHeight = width, height
+	return c
+}
+
+// SetFollowMode method sets how the camera moves towards its targets. The
+// smoothing is the rate, in fraction per second, for FollowLerp, and the
+// time in seconds for FollowDamped.
+func (c *Camera) SetFollowMode(mode FollowMode, smoothing float64) *Camera {
+	c.followMode, c.smoothing = mode, smoothing
+	c.velocityX, c.velocityY = 0, 0
+	return c
+}
+
+// SetFraming method zooms the camera to frame all targets when it follows
+// more than one, with the given margin in pixels around them, and zoom
+// between the given limits. A zero minimum zoom disables framing.
+func (c *Camera) SetFraming(margin, minZoom, maxZoom float64) *Camera {
+	c.framingMargin, c.minZoom, c.maxZoom = margin, minZoom, maxZoom
+	return c
+}
+
+// SetLimits method sets the map size the view is kept inside while following
+// targets. Zero size disables limits.
+func (c *Camera) SetLimits(width, height float64) *Camera {
+	c.limitWidth, c.limitHeight = width, height
+	return c
+}
+
+// SetLookAhead method moves the camera the given distance in pixels ahead of
+// the targets movement direction, changing at the given rate, in fraction
+// per second.
+func (c *Camera) SetLookAhead(distance, rate float64) *Camera {
+	c.lookAhead, c.lookAheadRate = distance, rate
+	return c
+}
+
+// SetRotation method sets the camera rotation in radians.
+func (c *Camera) SetRotation(rotation float64) *Camera {
+	c.Rotation = rotation
+	return c
+}
+
+// SetShake method sets the shake for the maximum trauma: the offset in
+// screen pixels, the rotation in radians, and the frequency of the shake
+// movement. Trauma decreases by the given decay every second.
+func (c *Camera) SetShake(offset, angle, frequency, decay float64) *Camera {
+	c.shakeOffset, c.shakeAngle = offset, angle
+	c.shakeFrequency, c.traumaDecay = frequency, decay
+	return c
+}
+
+// SetTarget method sets a single entity as the target the camera follows.
+func (c *Camera) SetTarget(target IEntity) *Camera {
+	c.targets = []IEntity{target}
+	return c
+}
+
+// SetZoom method sets the camera zoom, where values greater than one zoom in.
+func (c *Camera) SetZoom(zoom float64) *Camera {
+	if zoom > 0 {
+		c.Zoom = zoom
+	}
+	return c
+}
+
+// Snap method makes the next Update center the targets immediately, without
+// smoothing, like after a teleport or a scene change.
+func (c *Camera) Snap() *Camera {
+	c.following = false
+	return c
+}
+
+// StopPan method stops any pan, and the camera moves back to its targets
+// with its follow mode.
+func (c *Camera) StopPan() *Camera {
+	c.pan = nil
+	return c
+}
+
+// Update method moves the camera towards its targets, or along the running
+// pan, and updates shake and overlay effects, for the given elapsed time in
+// seconds. It has to be called every simulation step, after targets have
+// moved.
+func (c *Camera) Update(dt float64) {
+	c.prevX, c.prevY = c.X, c.Y
+	c.updateShake(dt)
+	if overlay := c.overlay; overlay != nil {
+		overlay.Update(dt)
+		// the overlay can be replaced by its own completion callback.
+		if overlay.IsFinished() && c.overlay == overlay {
+			c.overlay = nil
+		}
+	}
+	if c.pan != nil {
+		c.updatePan(dt)
+		return
+	}
+	c.updateFollow(dt)
+}
+
+// WorldToScreen method returns the screen position for the given world
+// position.
+func (c *Camera) WorldToScreen(x, y float64) (float64, float64) {
+	view := c.GetViewMatrix()
+	return view.Apply(x, y)
+}
+
+// CameraPan structure defines a scripted camera move from the camera center
+// when the pan starts to a destination, which can move, like an entity,
+// with easing. The pan finishes after the destination is reached and the
+// hold time has passed.
+type CameraPan struct {
+	camera       *Camera
+	fromX, fromY float64
+	destination  func(float64) (float64, float64)
+	duration     float64
+	hold         float64
+	elapsed      float64
+	ease         tools.EasingFunc
+	release      bool
+	started      bool
+	finished     bool
+	onComplete   func()
+}
+
+// newCameraPan function creates a new CameraPan instance for the camera. The
+// destination function returns the camera center for the elapsed time in
+// seconds. Release pans give the camera back to its targets when finished.
+func newCameraPan(camera *Camera, destination func(float64) (float64, float64), duration float64, release bool) *CameraPan {
+	return &CameraPan{
+		camera:      camera,
+		destination: destination,
+		duration:    duration,
+		ease:        tools.EaseLinear,
+		release:     release,
+	}
+}
+
+// -----------------------------------------------------------------------------
+// CameraPan public methods
+// -----------------------------------------------------------------------------
+
+func (p *CameraPan) IsFinished() bool {
+	return p.finished
+}
+
+// OnComplete method sets the function called when the pan finishes, like
+// starting the next cutscene step or ReturnToFollow.
+func (p *CameraPan) OnComplete(callback func()) *CameraPan {
+	p.onComplete = callback
+	return p
+}
+
+func (p *CameraPan) Reset() {
+	p.elapsed = 0
+	p.started = false
+	p.finished = false
+}
+
+func (p *CameraPan) SetEase(ease tools.EasingFunc) *CameraPan {
+	p.ease = ease
+	return p
+}
+
+// SetHold method sets the time, in seconds, the pan waits at the destination
+// before it finishes.
+func (p *CameraPan) SetHold(hold float64) *CameraPan {
+	p.hold = hold
+	return p
+}
+
+func (p *CameraPan) Update(dt float64) float64 {
+	if p.finished {
+		return dt
+	}
+	if !p.started {
+		p.fromX, p.fromY = p.camera.GetCenter()
+		p.started = true
+	}
+	p.elapsed += dt
+	progress := 1.0
+	if p.duration > 0 {
+		progress = math.Min(p.elapsed/p.duration, 1)
+	}
+	toX, toY := p.destination(dt)
+	progress = p.ease(progress)
+	p.camera.setCenter(tools.Lerp(p.fromX, toX, progress), tools.Lerp(p.fromY, toY, progress))
+	if p.elapsed < p.duration+p.hold {
+		return 0
+	}
+	p.finished = true
+	if p.onComplete != nil {
+		p.onComplete()
+	}
+	return p.elapsed - p.duration - p.hold
+}
+
+var _ ITween = (*CameraPan)(nil)
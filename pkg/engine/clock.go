@@ -0,0 +1,156 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	// DefaultFixedStep is the default simulation step, in seconds.
+	DefaultFixedStep = 1.0 / 60.0
+
+	// DefaultMaxSteps is the default maximum number of simulation steps run
+	// for a single frame, which avoids the simulation falling further behind
+	// when a frame takes too long.
+	DefaultMaxSteps = 5
+)
+
+// Clock structure defines the simulation clock. It accumulates the elapsed
+// time for every frame and splits it into fixed steps, so the simulation
+// behaves the same for any TPS or frame rate.
+//
+// Time can be scaled for slow motion, or paused. The time left in the
+// accumulator after running all steps is available as the interpolation
+// alpha, which draws use to blend the previous and the current state.
+//
+// Example usage:
+//
+//	steps := clock.Advance(1 / float64(ebiten.TPS()))
+//	for i := 0; i < steps; i++ {
+//		ctx.Advance(clock.GetStep())
+//		// update the simulation.
+//	}
+type Clock struct {
+	step        float64
+	accumulator float64
+	scale       float64
+	paused      bool
+	maxSteps    int
+	time        float64
+	steps       uint64
+	lastAdvance time.Time
+	now         func() time.Time
+}
+
+// NewClock function creates a new Clock instance with the given fixed step,
+// in seconds.
+func NewClock(step float64) *Clock {
+	return &Clock{
+		step:     step,
+		scale:    1,
+		maxSteps: DefaultMaxSteps,
+		now:      time.Now,
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Clock public methods
+// -----------------------------------------------------------------------------
+
+// Advance method adds the given frame time, in seconds, scaled by the clock
+// time scale, to the accumulator and returns the number of fixed steps to
+// run. Nothing is accumulated while the clock is paused.
+func (c *Clock) Advance(frameDelta float64) int {
+	c.lastAdvance = c.now()
+	if c.paused || c.step <= 0 {
+		return 0
+	}
+	c.accumulator += frameDelta * c.scale
+	steps := 0
+	for c.accumulator >= c.step {
+		if steps == c.maxSteps {
+			// drop the time that can not be simulated in this frame.
+			c.accumulator = 0
+			break
+		}
+		c.accumulator -= c.step
+		steps++
+	}
+	c.time += float64(steps) * c.step
+	c.steps += uint64(steps)
+	return steps
+}
+
+// AdvanceTick method advances the clock by one ebiten tick.
+func (c *Clock) AdvanceTick() int {
+	return c.Advance(1 / float64(ebiten.TPS()))
+}
+
+// GetAlpha method returns the interpolation alpha between the previous and
+// the current simulation state, between 0 and 1. It includes the real time
+// since the last call to Advance, so draws between two updates are blended
+// too.
+func (c *Clock) GetAlpha() float64 {
+	if c.step <= 0 {
+		return 1
+	}
+	accumulator := c.accumulator
+	if !c.paused && !c.lastAdvance.IsZero() {
+		accumulator += c.now().Sub(c.lastAdvance).Seconds() * c.scale
+	}
+	return min(accumulator/c.step, 1)
+}
+
+func (c *Clock) GetMaxSteps() int {
+	return c.maxSteps
+}
+
+func (c *Clock) GetScale() float64 {
+	return c.scale
+}
+
+func (c *Clock) GetStep() float64 {
+	return c.step
+}
+
+// GetSteps method returns the number of fixed steps run since the clock was
+// created.
+func (c *Clock) GetSteps() uint64 {
+	return c.steps
+}
+
+// GetTime method returns the simulated time, in seconds, since the clock was
+// created.
+func (c *Clock) GetTime() float64 {
+	return c.time
+}
+
+func (c *Clock) IsPaused() bool {
+	return c.paused
+}
+
+func (c *Clock) SetMaxSteps(maxSteps int) *Clock {
+	c.maxSteps = maxSteps
+	return c
+}
+
+// SetNow method sets the function used to read the real time, which allows
+// the clock to be driven from tests.
+func (c *Clock) SetNow(now func() time.Time) *Clock {
+	c.now = now
+	return c
+}
+
+// SetPaused method pauses or resumes the simulation.
+func (c *Clock) SetPaused(paused bool) *Clock {
+	c.paused = paused
+	return c
+}
+
+// SetScale method sets the time scale, where one is real time, values lower
+// than one are slow motion and zero freezes the simulation.
+func (c *Clock) SetScale(scale float64) *Clock {
+	c.scale = scale
+	return c
+}
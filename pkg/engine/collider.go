@@ -1,14 +1,148 @@
 package engine
 
-import "image"
+import (
+	"math"
 
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jrecuero/ebiplay/pkg/tools"
+)
+
+// CollisionLayer type defines a bitset of collision layers. Every collider
+// belongs to the layers in its layer bitset, and collides with colliders in
+// the layers in its mask bitset.
+type CollisionLayer uint32
+
+const (
+	// CollisionLayerDefault is the layer for colliders without any layer set.
+	CollisionLayerDefault CollisionLayer = 1 << iota
+)
+
+// CollisionMaskAll is the mask that collides with all layers, and the
+// default mask for colliders.
+const CollisionMaskAll CollisionLayer = ^CollisionLayer(0)
+
+// ICollider interface defines an object that takes part in collisions.
+//
+// Solid colliders block movement, while triggers only report the overlap to
+// the colliders going through them. IsSolid returns false for triggers.
+//
+// GetShape returns the collider shape in world coordinates, used to check
+// collisions precisely, and GetBounds the axis aligned rectangle that
+// contains it, used in the broad phase.
 type ICollider interface {
-	CollideWith(IEntity)
-	GetBounds() image.Rectangle
+	GetBounds() tools.Rect
+	GetCollisionLayer() CollisionLayer
+	GetCollisionMask() CollisionLayer
+	GetShape() tools.IShape
 	IsSolid() bool
+	IsTrigger() bool
+}
+
+// IOneWay interface defines a collider that only blocks colliders moving down
+// onto its top side, like a platform that can be jumped through from below.
+type IOneWay interface {
+	IsOneWay() bool
+}
+
+// CanCollide function returns if both colliders interact, which requires
+// every collider to be in a layer included in the mask of the other one.
+func CanCollide(a, b ICollider) bool {
+	return a.GetCollisionLayer()&b.GetCollisionMask() != 0 && b.GetCollisionLayer()&a.GetCollisionMask() != 0
+}
+
+// CheckShapes function returns if the shapes for both colliders overlap.
+func CheckShapes(a, b ICollider) bool {
+	return tools.Overlaps(a.GetShape(), b.GetShape())
+}
+
+// IsSameCollider function returns if both colliders are the same object,
+// comparing IDs so an object embedding a collider matches it.
+func IsSameCollider(a, b ICollider) bool {
+	if a == b {
+		return true
+	}
+	baseA, okA := a.(IBase)
+	baseB, okB := b.(IBase)
+	return okA && okB && baseA.GetID() == baseB.GetID()
 }
 
 func CheckCollidable(obj any) (ICollider, bool) {
 	result, ok := obj.(ICollider)
 	return result, ok
 }
+
+// TransformShape function returns the shape with all points moved by the
+// given transform, like an entity world transform. Circle and capsule radii
+// are scaled by the average transform scale, because non uniform scales and
+// skews would turn them into ellipses.
+func TransformShape(shape tools.IShape, geoM ebiten.GeoM) tools.IShape {
+	apply := func(v tools.Vec) tools.Vec {
+		x, y := geoM.Apply(v.X, v.Y)
+		return tools.Vec{X: x, Y: y}
+	}
+	a, b, c, d := geoM.Element(0, 0), geoM.Element(0, 1), geoM.Element(1, 0), geoM.Element(1, 1)
+	scale := math.Sqrt(math.Abs(a*d - b*c))
+	switch s := shape.(type) {
+	case *tools.Circle:
+		return &tools.Circle{Center: apply(s.Center), Radius: s.Radius * scale}
+	case *tools.Capsule:
+		return &tools.Capsule{A: apply(s.A), B: apply(s.B), Radius: s.Radius * scale}
+	case *tools.Polygon:
+		points := make([]tools.Vec, len(s.Points))
+		for i, point := range s.Points {
+			points[i] = apply(point)
+		}
+		return tools.NewPolygon(points...)
+	}
+	x, y := geoM.Apply(0, 0)
+	return shape.Transform(x, y, 0, 1)
+}
+
+// isBoxShape function returns if the shape is an axis aligned box, which is
+// the same as its bounds.
+func isBoxShape(shape tools.IShape) bool {
+	polygon, ok := shape.(*tools.Polygon)
+	if !ok || len(polygon.Points) != 4 {
+		return false
+	}
+	for i, point := range polygon.Points {
+		next := polygon.Points[(i+1)%len(polygon.Points)]
+		if math.Abs(point.X-next.X) > tools.Epsilon && math.Abs(point.Y-next.Y) > tools.Epsilon {
+			return false
+		}
+	}
+	return true
+}
+
+// ICollisionEnter interface defines a collider notified when it starts
+// touching or overlapping other collider.
+type ICollisionEnter interface {
+	OnCollisionEnter(ICollider)
+}
+
+// ICollisionStay interface defines a collider notified every step it keeps
+// touching or overlapping other collider.
+type ICollisionStay interface {
+	OnCollisionStay(ICollider)
+}
+
+// ICollisionExit interface defines a collider notified when it stops
+// touching or overlapping other collider.
+type ICollisionExit interface {
+	OnCollisionExit(ICollider)
+}
+
+// Topics published in the collision world event bus, with a CollisionEvent
+// payload.
+const (
+	CollisionEnterTopic = "collision.enter"
+	CollisionStayTopic  = "collision.stay"
+	CollisionExitTopic  = "collision.exit"
+)
+
+// CollisionEvent structure defines the payload for collision topics: both
+// colliders in contact, with the collider added first to the collision world
+// in the first place.
+type CollisionEvent struct {
+	A, B ICollider
+}
@@ -0,0 +1,569 @@
+package engine
+
+import (
+	"math"
+	"slices"
+
+	"github.com/jrecuero/ebiplay/pkg/tools"
+)
+
+// DefaultCollisionCellSize is the default spatial hash cell size, in pixels.
+const DefaultCollisionCellSize = 64
+
+// cellKey type defines the coordinates for a cell in the spatial hash.
+type cellKey struct {
+	x, y int
+}
+
+// colliderEntry structure keeps a collider with the bounds and cells it was
+// stored with, and the order it was added, used to return results in a
+// deterministic order.
+type colliderEntry struct {
+	collider ICollider
+	bounds   tools.Rect
+	cells    []cellKey
+	order    int
+}
+
+// contactPair type defines two entries in contact, with the entry added
+// first in the first place.
+type contactPair struct {
+	a, b *colliderEntry
+}
+
+// newContactPair function creates a new contactPair instance with the entries
+// in the order they were added.
+func newContactPair(a, b *colliderEntry) contactPair {
+	if a.order > b.order {
+		a, b = b, a
+	}
+	return contactPair{a, b}
+}
+
+// CollisionWorld structure defines the broad phase for collision detection.
+// Colliders are stored in a spatial hash, a grid of cells where every
+// collider is registered in all cells its bounds overlap, so only colliders
+// sharing a cell have to be checked against each other.
+//
+// Colliders have to be updated when they move, calling Update for a single
+// collider or UpdateAll once every simulation step.
+//
+// Queries like raycasts, overlaps and nearest colliders are filtered by a
+// layer mask, and they check the tile collision grid too, when it is set.
+//
+// The world tracks contacts across steps: colliders overlapping and contacts
+// reported with AddContact, usually by MoveAndCollide. UpdateContacts has to
+// be called once at the end of every simulation step to deliver enter, stay
+// and exit callbacks to colliders implementing them, and to publish them in
+// the event bus, when it is set.
+type CollisionWorld struct {
+	cellSize int
+	entries  map[ICollider]*colliderEntry
+	byID     map[string]*colliderEntry
+	cells    map[cellKey][]*colliderEntry
+	order    int
+	contacts map[contactPair]bool
+	reported map[contactPair]bool
+	bus      *EventBus
+	tileGrid *TileCollisionGrid
+}
+
+// NewCollisionWorld function creates a new CollisionWorld instance with the
+// given cell size in pixels. Cells should be about the size of the largest
+// moving colliders.
+func NewCollisionWorld(cellSize int) *CollisionWorld {
+	if cellSize <= 0 {
+		cellSize = DefaultCollisionCellSize
+	}
+	return &CollisionWorld{
+		cellSize: cellSize,
+		entries:  make(map[ICollider]*colliderEntry),
+		byID:     make(map[string]*colliderEntry),
+		cells:    make(map[cellKey][]*colliderEntry),
+		contacts: make(map[contactPair]bool),
+		reported: make(map[contactPair]bool),
+	}
+}
+
+// -----------------------------------------------------------------------------
+// CollisionWorld private methods
+// -----------------------------------------------------------------------------
+
+// isLinked method returns if both colliders in the pair are still in the
+// world, because callbacks can remove colliders while contacts are
+// delivered.
+func (w *CollisionWorld) isLinked(pair contactPair) bool {
+	return w.entries[pair.a.collider] == pair.a && w.entries[pair.b.collider] == pair.b
+}
+
+// notify method delivers the collision callback for the given topic to both
+// colliders in the pair, and publishes it in the event bus.
+func (w *CollisionWorld) notify(topic string, pair contactPair) {
+	a, b := pair.a.collider, pair.b.collider
+	switch topic {
+	case CollisionEnterTopic:
+		if handler, ok := a.(ICollisionEnter); ok {
+			handler.OnCollisionEnter(b)
+		}
+		if handler, ok := b.(ICollisionEnter); ok {
+			handler.OnCollisionEnter(a)
+		}
+	case CollisionStayTopic:
+		if handler, ok := a.(ICollisionStay); ok {
+			handler.OnCollisionStay(b)
+		}
+		if handler, ok := b.(ICollisionStay); ok {
+			handler.OnCollisionStay(a)
+		}
+	case CollisionExitTopic:
+		if handler, ok := a.(ICollisionExit); ok {
+			handler.OnCollisionExit(b)
+		}
+		if handler, ok := b.(ICollisionExit); ok {
+			handler.OnCollisionExit(a)
+		}
+	}
+	if w.bus != nil {
+		w.bus.Publish(topic, CollisionEvent{A: a, B: b})
+	}
+}
+
+// clampDistance method returns the given query distance, or the distance to
+// the farthest corner of the world extent, containing all colliders and the
+// tile grid, for infinite distances, so query regions are always finite. It
+// returns false for distances that are not a number or negative.
+func (w *CollisionWorld) clampDistance(x, y, maxDistance float64) (float64, bool) {
+	if math.IsNaN(maxDistance) || maxDistance < 0 {
+		return 0, false
+	}
+	if !math.IsInf(maxDistance, 1) {
+		return maxDistance, true
+	}
+	extent := tools.NewRect(x, y, 0, 0)
+	for _, entry := range w.entries {
+		extent = extent.Union(entry.bounds)
+	}
+	if w.tileGrid != nil {
+		width, height := w.tileGrid.GetSize()
+		tileWidth, tileHeight := w.tileGrid.GetTileSize()
+		extent = extent.Union(tools.NewRect(0, 0, float64(width*tileWidth), float64(height*tileHeight)))
+	}
+	dx := math.Max(x-extent.X, extent.Right()-x)
+	dy := math.Max(y-extent.Y, extent.Bottom()-y)
+	return math.Hypot(dx, dy), true
+}
+
+// getCellRange method returns the first and last cells the given bounds
+// overlap.
+func (w *CollisionWorld) getCellRange(bounds tools.Rect) (cellKey, cellKey) {
+	size := float64(w.cellSize)
+	minCell := cellKey{
+		x: int(math.Floor(bounds.X / size)),
+		y: int(math.Floor(bounds.Y / size)),
+	}
+	// bounds are half-open, so the max edge belongs to the previous cell.
+	maxCell := cellKey{
+		x: int(math.Floor(math.Max(bounds.Right()-tools.Epsilon, bounds.X) / size)),
+		y: int(math.Floor(math.Max(bounds.Bottom()-tools.Epsilon, bounds.Y) / size)),
+	}
+	return minCell, maxCell
+}
+
+// filter method returns all colliders in the region in layers matching the
+// mask that are not excluded.
+func (w *CollisionWorld) filter(region tools.Rect, mask CollisionLayer, exclude []ICollider) []ICollider {
+	var result []ICollider
+	for _, collider := range w.QueryRect(region) {
+		if collider.GetCollisionLayer()&mask == 0 {
+			continue
+		}
+		if slices.ContainsFunc(exclude, func(other ICollider) bool { return IsSameCollider(collider, other) }) {
+			continue
+		}
+		result = append(result, collider)
+	}
+	return result
+}
+
+// getEntry method returns the entry for the collider, looking it up by ID
+// for objects embedding a collider in the world.
+func (w *CollisionWorld) getEntry(collider ICollider) (*colliderEntry, bool) {
+	if entry, ok := w.entries[collider]; ok {
+		return entry, true
+	}
+	if base, ok := collider.(IBase); ok {
+		entry, ok := w.byID[base.GetID()]
+		return entry, ok
+	}
+	return nil, false
+}
+
+// insert method registers the entry in all cells for its bounds.
+func (w *CollisionWorld) insert(entry *colliderEntry) {
+	entry.bounds = entry.collider.GetBounds()
+	entry.cells = entry.cells[:0]
+	minCell, maxCell := w.getCellRange(entry.bounds)
+	for y := minCell.y; y <= maxCell.y; y++ {
+		for x := minCell.x; x <= maxCell.x; x++ {
+			key := cellKey{x, y}
+			w.cells[key] = append(w.cells[key], entry)
+			entry.cells = append(entry.cells, key)
+		}
+	}
+}
+
+// sortPairs function sorts contact pairs by the order their entries were
+// added, so callbacks are delivered in a deterministic order.
+func sortPairs(pairs map[contactPair]bool) []contactPair {
+	result := make([]contactPair, 0, len(pairs))
+	for pair := range pairs {
+		result = append(result, pair)
+	}
+	slices.SortFunc(result, func(x, y contactPair) int {
+		if x.a.order != y.a.order {
+			return x.a.order - y.a.order
+		}
+		return x.b.order - y.b.order
+	})
+	return result
+}
+
+// query method returns all entries in cells overlapped by the given bounds,
+// sorted by the order they were added, without duplicates.
+func (w *CollisionWorld) query(bounds tools.Rect) []*colliderEntry {
+	var result []*colliderEntry
+	seen := make(map[*colliderEntry]bool)
+	minCell, maxCell := w.getCellRange(bounds)
+	for y := minCell.y; y <= maxCell.y; y++ {
+		for x := minCell.x; x <= maxCell.x; x++ {
+			for _, entry := range w.cells[cellKey{x, y}] {
+				if !seen[entry] {
+					seen[entry] = true
+					result = append(result, entry)
+				}
+			}
+		}
+	}
+	slices.SortFunc(result, func(a, b *colliderEntry) int {
+		return a.order - b.order
+	})
+	return result
+}
+
+// unlink method removes the entry from all cells it was registered in.
+func (w *CollisionWorld) unlink(entry *colliderEntry) {
+	for _, key := range entry.cells {
+		cell := w.cells[key]
+		if i := slices.Index(cell, entry); i != -1 {
+			cell = slices.Delete(cell, i, i+1)
+		}
+		if len(cell) == 0 {
+			delete(w.cells, key)
+		} else {
+			w.cells[key] = cell
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+// CollisionWorld public methods
+// -----------------------------------------------------------------------------
+
+// Add method adds the collider to the world. Colliders already in the world
+// are updated.
+func (w *CollisionWorld) Add(collider ICollider) {
+	if _, ok := w.entries[collider]; ok {
+		w.Update(collider)
+		return
+	}
+	w.order++
+	entry := &colliderEntry{
+		collider: collider,
+		order:    w.order,
+	}
+	w.entries[collider] = entry
+	if base, ok := collider.(IBase); ok {
+		w.byID[base.GetID()] = entry
+		trackIDIndex(base, 1)
+	}
+	w.insert(entry)
+}
+
+// AddContact method reports both colliders are touching in the current step,
+// even if their bounds do not overlap, like an actor stopped against a wall.
+// Colliders can be objects embedding a collider in the world. Contacts are
+// delivered in the next UpdateContacts.
+func (w *CollisionWorld) AddContact(a, b ICollider) {
+	entryA, okA := w.getEntry(a)
+	entryB, okB := w.getEntry(b)
+	if okA && okB && entryA != entryB {
+		w.reported[newContactPair(entryA, entryB)] = true
+	}
+}
+
+// GetCandidates method returns all colliders that share a cell with the given
+// collider and whose shapes overlap it, excluding the collider itself and
+// colliders in layers it can not collide with.
+func (w *CollisionWorld) GetCandidates(collider ICollider) []ICollider {
+	bounds := collider.GetBounds()
+	var result []ICollider
+	for _, entry := range w.query(bounds) {
+		if entry.collider != collider && entry.bounds.Overlaps(bounds) && CanCollide(collider, entry.collider) && CheckShapes(collider, entry.collider) {
+			result = append(result, entry.collider)
+		}
+	}
+	return result
+}
+
+func (w *CollisionWorld) GetCellSize() int {
+	return w.cellSize
+}
+
+// GetColliders method returns all colliders in the world, in the order they
+// were added.
+func (w *CollisionWorld) GetColliders() []ICollider {
+	entries := make([]*colliderEntry, 0, len(w.entries))
+	for _, entry := range w.entries {
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b *colliderEntry) int {
+		return a.order - b.order
+	})
+	result := make([]ICollider, len(entries))
+	for i, entry := range entries {
+		result[i] = entry.collider
+	}
+	return result
+}
+
+func (w *CollisionWorld) GetEventBus() *EventBus {
+	return w.bus
+}
+
+// GetPairs method returns all pairs of colliders whose shapes overlap and can
+// collide with each other. Every pair is returned once, with the collider
+// added first in the first place.
+func (w *CollisionWorld) GetPairs() [][2]ICollider {
+	var result [][2]ICollider
+	for _, collider := range w.GetColliders() {
+		entry := w.entries[collider]
+		for _, other := range w.query(entry.bounds) {
+			if other.order > entry.order && other.bounds.Overlaps(entry.bounds) && CanCollide(entry.collider, other.collider) && CheckShapes(entry.collider, other.collider) {
+				result = append(result, [2]ICollider{entry.collider, other.collider})
+			}
+		}
+	}
+	return result
+}
+
+func (w *CollisionWorld) GetTileGrid() *TileCollisionGrid {
+	return w.tileGrid
+}
+
+// Has method returns if the collider is in the world.
+func (w *CollisionWorld) Has(collider ICollider) bool {
+	_, ok := w.entries[collider]
+	return ok
+}
+
+func (w *CollisionWorld) Len() int {
+	return len(w.entries)
+}
+
+// Nearest method returns the collider closest to the given point, measured
+// to its bounds center, up to the given distance, in layers matching the mask
+// and not excluded.
+func (w *CollisionWorld) Nearest(x, y, maxDistance float64, mask CollisionLayer, exclude ...ICollider) (ICollider, float64, bool) {
+	maxDistance, ok := w.clampDistance(x, y, maxDistance)
+	if !ok {
+		return nil, 0, false
+	}
+	region := tools.NewRect(x-maxDistance, y-maxDistance, 2*maxDistance, 2*maxDistance)
+	var nearest ICollider
+	best := maxDistance
+	for _, collider := range w.filter(region, mask, exclude) {
+		cx, cy := collider.GetBounds().Center()
+		if distance := math.Hypot(cx-x, cy-y); distance <= best {
+			nearest, best = collider, distance
+		}
+	}
+	return nearest, best, nearest != nil
+}
+
+// OverlapBox method returns all colliders overlapping the given rectangle, in
+// layers matching the mask and not excluded.
+func (w *CollisionWorld) OverlapBox(rect tools.Rect, mask CollisionLayer, exclude ...ICollider) []ICollider {
+	return w.OverlapShape(tools.NewBox(rect.X, rect.Y, rect.W, rect.H), mask, exclude...)
+}
+
+// OverlapCircle method returns all colliders overlapping the given circle, in
+// layers matching the mask and not excluded.
+func (w *CollisionWorld) OverlapCircle(x, y, radius float64, mask CollisionLayer, exclude ...ICollider) []ICollider {
+	return w.OverlapShape(tools.NewCircle(x, y, radius), mask, exclude...)
+}
+
+// OverlapShape method returns all colliders overlapping the given shape, in
+// layers matching the mask and not excluded, in the order they were added.
+func (w *CollisionWorld) OverlapShape(shape tools.IShape, mask CollisionLayer, exclude ...ICollider) []ICollider {
+	var result []ICollider
+	for _, collider := range w.filter(shape.GetBounds(), mask, exclude) {
+		if tools.Overlaps(shape, collider.GetShape()) {
+			result = append(result, collider)
+		}
+	}
+	return result
+}
+
+// QueryRect method returns all colliders whose bounds overlap the given
+// region, in the order they were added.
+func (w *CollisionWorld) QueryRect(region tools.Rect) []ICollider {
+	var result []ICollider
+	for _, entry := range w.query(region) {
+		if entry.bounds.Overlaps(region) {
+			result = append(result, entry.collider)
+		}
+	}
+	return result
+}
+
+// Raycast method returns the first solid collider or tile hit by the ray
+// from the given point along the given direction, up to the given distance.
+// Only colliders in layers matching the mask and not excluded are checked,
+// and triggers are ignored, so it can be used for line of sight checks.
+// Infinite distances are clamped to the world extent.
+func (w *CollisionWorld) Raycast(x, y, dirX, dirY, maxDistance float64, mask CollisionLayer, exclude ...ICollider) (RaycastHit, bool) {
+	origin := tools.Vec{X: x, Y: y}
+	dir := tools.Vec{X: dirX, Y: dirY}.Normalize()
+	if dir == (tools.Vec{}) {
+		return RaycastHit{}, false
+	}
+	maxDistance, ok := w.clampDistance(x, y, maxDistance)
+	if !ok {
+		return RaycastHit{}, false
+	}
+	// region is grown by one pixel, so rays along an axis have some area.
+	end := origin.Add(dir.Scale(maxDistance))
+	region := tools.NewRect(math.Min(x, end.X)-1, math.Min(y, end.Y)-1, math.Abs(end.X-x)+2, math.Abs(end.Y-y)+2)
+	var result RaycastHit
+	found := false
+	best := maxDistance
+	for _, collider := range w.filter(region, mask, exclude) {
+		if !collider.IsSolid() {
+			continue
+		}
+		distance, normal, ok := tools.RaycastShape(origin, dir, best, collider.GetShape())
+		if ok && (!found || distance < best) {
+			best, found = distance, true
+			result = RaycastHit{Collider: collider, NormalX: normal.X, NormalY: normal.Y, Distance: distance}
+		}
+	}
+	if w.tileGrid != nil && w.tileGrid.GetCollisionLayer()&mask != 0 {
+		tile, distance, normal, ok := w.tileGrid.Raycast(origin, dir, best)
+		if ok && (!found || distance < best) {
+			best, found = distance, true
+			result = RaycastHit{Tile: tile, IsTile: true, NormalX: normal.X, NormalY: normal.Y, Distance: distance}
+		}
+	}
+	if found {
+		point := origin.Add(dir.Scale(best))
+		result.X, result.Y = point.X, point.Y
+	}
+	return result, found
+}
+
+// Remove method removes the collider from the world. Exit callbacks are
+// delivered for all contacts the collider had.
+func (w *CollisionWorld) Remove(collider ICollider) {
+	entry, ok := w.entries[collider]
+	if !ok {
+		return
+	}
+	w.unlink(entry)
+	delete(w.entries, collider)
+	if base, ok := collider.(IBase); ok {
+		delete(w.byID, base.GetID())
+		trackIDIndex(base, -1)
+	}
+	for pair := range w.reported {
+		if pair.a == entry || pair.b == entry {
+			delete(w.reported, pair)
+		}
+	}
+	for _, pair := range sortPairs(w.contacts) {
+		if pair.a == entry || pair.b == entry {
+			delete(w.contacts, pair)
+			w.notify(CollisionExitTopic, pair)
+		}
+	}
+}
+
+// SegmentCast method returns the first solid collider or tile hit by the
+// segment between both points, like Raycast.
+func (w *CollisionWorld) SegmentCast(x1, y1, x2, y2 float64, mask CollisionLayer, exclude ...ICollider) (RaycastHit, bool) {
+	return w.Raycast(x1, y1, x2-x1, y2-y1, math.Hypot(x2-x1, y2-y1), mask, exclude...)
+}
+
+// SetEventBus method sets the event bus collision topics are published in.
+func (w *CollisionWorld) SetEventBus(bus *EventBus) *CollisionWorld {
+	w.bus = bus
+	return w
+}
+
+// SetTileGrid method sets the tile collision grid checked in queries.
+func (w *CollisionWorld) SetTileGrid(grid *TileCollisionGrid) *CollisionWorld {
+	w.tileGrid = grid
+	return w
+}
+
+// Update method moves the collider to the cells for its current bounds. It
+// does nothing if the bounds have not changed.
+func (w *CollisionWorld) Update(collider ICollider) {
+	entry, ok := w.entries[collider]
+	if !ok || entry.bounds == collider.GetBounds() {
+		return
+	}
+	w.unlink(entry)
+	w.insert(entry)
+}
+
+// UpdateAll method updates all colliders in the world.
+func (w *CollisionWorld) UpdateAll() {
+	for collider := range w.entries {
+		w.Update(collider)
+	}
+}
+
+// UpdateContacts method compares contacts in the current step, colliders
+// overlapping and contacts reported, with contacts in the previous step, and
+// delivers enter callbacks for new contacts, stay callbacks for contacts
+// kept and exit callbacks for contacts lost.
+func (w *CollisionWorld) UpdateContacts() {
+	current := w.reported
+	w.reported = make(map[contactPair]bool)
+	for _, pair := range w.GetPairs() {
+		current[newContactPair(w.entries[pair[0]], w.entries[pair[1]])] = true
+	}
+	// pairs with colliders removed by a callback are skipped, because Remove
+	// has already delivered their exit.
+	for _, pair := range sortPairs(w.contacts) {
+		if !current[pair] && w.isLinked(pair) {
+			w.notify(CollisionExitTopic, pair)
+		}
+	}
+	for _, pair := range sortPairs(current) {
+		if !w.isLinked(pair) {
+			continue
+		}
+		if w.contacts[pair] {
+			w.notify(CollisionStayTopic, pair)
+		} else {
+			w.notify(CollisionEnterTopic, pair)
+		}
+	}
+	for pair := range current {
+		if !w.isLinked(pair) {
+			delete(current, pair)
+		}
+	}
+	w.contacts = current
+}
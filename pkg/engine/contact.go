@@ -0,0 +1,37 @@
+package engine
+
+import "image"
+
+// Contact structure defines a collision found while moving an actor: the
+// collider hit, or a solid tile when IsTile is true, and the contact normal
+// on its surface, pointing towards the actor.
+type Contact struct {
+	Collider         ICollider
+	Tile             image.Point
+	IsTile           bool
+	NormalX, NormalY float64
+}
+
+// RaycastHit structure defines the first thing hit by a ray in the collision
+// world: a collider, or a solid tile when IsTile is true, the hit point, the
+// normal on the surface hit and the distance from the ray origin.
+type RaycastHit struct {
+	Collider         ICollider
+	Tile             image.Point
+	IsTile           bool
+	X, Y             float64
+	NormalX, NormalY float64
+	Distance         float64
+}
+
+// appendContact function appends the contact, unless there is already a
+// contact for the same collider or tile.
+func appendContact(contacts []Contact, contact Contact) []Contact {
+	for _, c := range contacts {
+		if c.IsTile == contact.IsTile && c.Tile == contact.Tile &&
+			(c.IsTile || IsSameCollider(c.Collider, contact.Collider)) {
+			return contacts
+		}
+	}
+	return append(contacts, contact)
+}
@@ -0,0 +1,91 @@
+package engine
+
+import (
+	"github.com/jrecuero/ebiplay/pkg/ecs"
+)
+
+// ActorComponent structure defines the ECS component that links an entity to
+// an existing actor, so actors can be part of an ECS world and systems can
+// work with them.
+type ActorComponent struct {
+	Actor IActor
+}
+
+// PositionComponent structure defines the ECS component for a position in
+// world coordinates.
+type PositionComponent struct {
+	X, Y float64
+}
+
+// VelocityComponent structure defines the ECS component for a velocity in
+// pixels per second.
+type VelocityComponent struct {
+	DX, DY float64
+}
+
+// AddActorToWorld function creates a new entity in the ECS world for the
+// given actor. The entity has an ActorComponent and a PositionComponent with
+// the actor position.
+func AddActorToWorld(world *ecs.World, actor IActor) ecs.EntityID {
+	id := world.NewEntity()
+	ecs.AddComponent(world, id, ActorComponent{Actor: actor})
+	x, y := actor.GetPos()
+	ecs.AddComponent(world, id, PositionComponent{X: x, Y: y})
+	return id
+}
+
+// NewActorSubmitSystem function creates a system that adds all actors in the
+// world to the given render queue.
+func NewActorSubmitSystem(queue *RenderQueue) ecs.ISystem {
+	return ecs.SystemFunc(func(world *ecs.World, dt float64) error {
+		ecs.Each1(world, func(_ ecs.EntityID, component *ActorComponent) {
+			component.Actor.Submit(queue)
+		})
+		return nil
+	})
+}
+
+// NewActorSyncSystem function creates a system that copies the position
+// component to the actor, so actors can be moved by other systems.
+func NewActorSyncSystem() ecs.ISystem {
+	return ecs.SystemFunc(func(world *ecs.World, dt float64) error {
+		ecs.Each2(world, func(_ ecs.EntityID, component *ActorComponent, pos *PositionComponent) {
+			component.Actor.SetPos(pos.X, pos.Y)
+		})
+		return nil
+	})
+}
+
+// NewActorUpdateSystem function creates a system that calls update for all
+// actors in the world with the given update context, which has to be
+// advanced by the game every frame. The position component is refreshed with
+// the actor position after the update.
+func NewActorUpdateSystem(ctx *UpdateContext) ecs.ISystem {
+	return ecs.SystemFunc(func(world *ecs.World, dt float64) error {
+		var err error
+		ecs.Each1(world, func(id ecs.EntityID, component *ActorComponent) {
+			if err != nil {
+				return
+			}
+			if err = component.Actor.Update(ctx); err != nil {
+				return
+			}
+			if pos, ok := ecs.GetComponent[PositionComponent](world, id); ok {
+				pos.X, pos.Y = component.Actor.GetPos()
+			}
+		})
+		return err
+	})
+}
+
+// NewMovementSystem function creates a system that moves every entity with
+// position and velocity components.
+func NewMovementSystem() ecs.ISystem {
+	return ecs.SystemFunc(func(world *ecs.World, dt float64) error {
+		ecs.Each2(world, func(_ ecs.EntityID, pos *PositionComponent, vel *VelocityComponent) {
+			pos.X += vel.DX * dt
+			pos.Y += vel.DY * dt
+		})
+		return nil
+	})
+}
@@ -0,0 +1,248 @@
+package engine
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/colorm"
+)
+
+// EffectKind type defines the kind of visual effect applied to a sprite.
+type EffectKind int
+
+const (
+	EffectTint EffectKind = iota
+	EffectFlash
+	EffectFade
+	EffectGrayscale
+	EffectOutline
+	EffectSilhouette
+)
+
+// Effect structure defines a visual effect applied to a sprite when it is
+// drawn.
+//
+// Every effect has an amount that goes from a start value to an end value
+// over its duration, in seconds. Effects with zero duration keep the start
+// amount and last until they are removed.
+type Effect struct {
+	kind       EffectKind
+	r, g, b, a float64
+	from, to   float64
+	thickness  int
+	duration   float64
+	elapsed    float64
+}
+
+// newEffect function creates a new Effect instance for the given color.
+func newEffect(kind EffectKind, clr color.Color, from, to float64, duration float64) *Effect {
+	effect := &Effect{
+		kind:     kind,
+		from:     from,
+		to:       to,
+		duration: duration,
+	}
+	if clr != nil {
+		r, g, b, a := clr.RGBA()
+		if a != 0 {
+			// color.Color returns alpha-premultiplied values.
+			effect.r = float64(r) / float64(a)
+			effect.g = float64(g) / float64(a)
+			effect.b = float64(b) / float64(a)
+		}
+		effect.a = float64(a) / 0xffff
+	}
+	return effect
+}
+
+// NewFadeEffect function creates an effect that changes sprite alpha from one
+// value to other.
+func NewFadeEffect(from, to float64, duration float64) *Effect {
+	return newEffect(EffectFade, nil, from, to, duration)
+}
+
+// NewFlashEffect function creates an effect that adds the given color to the
+// sprite, fading out over the duration.
+func NewFlashEffect(clr color.Color, duration float64) *Effect {
+	return newEffect(EffectFlash, clr, 1, 0, duration)
+}
+
+// NewGrayscaleEffect function creates an effect that removes the given amount
+// of saturation from the sprite.
+func NewGrayscaleEffect(amount float64, duration float64) *Effect {
+	return newEffect(EffectGrayscale, nil, amount, amount, duration)
+}
+
+// NewOutlineEffect function creates an effect that draws an outline with the
+// given color and thickness, in pixels, around the sprite.
+func NewOutlineEffect(clr color.Color, thickness int, duration float64) *Effect {
+	effect := newEffect(EffectOutline, clr, 1, 1, duration)
+	effect.thickness = thickness
+	return effect
+}
+
+// NewSilhouetteEffect function creates an effect that fills the sprite with
+// the given color, keeping its shape.
+func NewSilhouetteEffect(clr color.Color, duration float64) *Effect {
+	return newEffect(EffectSilhouette, clr, 1, 1, duration)
+}
+
+// NewTintEffect function creates an effect that multiplies the sprite color by
+// the given color.
+func NewTintEffect(clr color.Color, duration float64) *Effect {
+	return newEffect(EffectTint, clr, 1, 1, duration)
+}
+
+// -----------------------------------------------------------------------------
+// Effect public methods
+// -----------------------------------------------------------------------------
+
+// Apply method concatenates the effect color transformation to the given
+// color matrix.
+func (e *Effect) Apply(cm *colorm.ColorM) {
+	amount := e.GetAmount()
+	switch e.kind {
+	case EffectTint:
+		cm.Scale(1+(e.r-1)*amount, 1+(e.g-1)*amount, 1+(e.b-1)*amount, 1)
+	case EffectFlash:
+		cm.Translate(e.r*e.a*amount, e.g*e.a*amount, e.b*e.a*amount, 0)
+	case EffectFade:
+		cm.Scale(1, 1, 1, amount)
+	case EffectGrayscale:
+		cm.ChangeHSV(0, 1-amount, 1)
+	case EffectSilhouette:
+		cm.Scale(0, 0, 0, 1)
+		cm.Translate(e.r, e.g, e.b, 0)
+	}
+}
+
+// GetAmount method returns the effect amount for the current elapsed time.
+func (e *Effect) GetAmount() float64 {
+	if e.duration <= 0 {
+		return e.from
+	}
+	progress := e.elapsed / e.duration
+	if progress > 1 {
+		progress = 1
+	}
+	return e.from + (e.to-e.from)*progress
+}
+
+func (e *Effect) GetKind() EffectKind {
+	return e.kind
+}
+
+// IsExpired method returns if the effect duration has been completed.
+func (e *Effect) IsExpired() bool {
+	return e.duration > 0 && e.elapsed >= e.duration
+}
+
+// Update method increases the effect elapsed time by the given time in
+// seconds.
+func (e *Effect) Update(dt float64) {
+	e.elapsed += dt
+}
+
+// EffectStack structure defines the list of effects applied to a sprite, in
+// the order they were added.
+type EffectStack struct {
+	effects []*Effect
+}
+
+// NewEffectStack function creates a new empty EffectStack instance.
+func NewEffectStack() *EffectStack {
+	return &EffectStack{}
+}
+
+// -----------------------------------------------------------------------------
+// EffectStack private methods
+// -----------------------------------------------------------------------------
+
+// drawOutline method draws the image silhouette displaced around the sprite
+// position, so it is visible as an outline behind the sprite.
+func (s *EffectStack) drawOutline(screen *ebiten.Image, image *ebiten.Image, geoM ebiten.GeoM, effect *Effect, alpha float64) {
+	cm := colorm.ColorM{}
+	cm.Scale(0, 0, 0, effect.a*effect.GetAmount()*alpha)
+	cm.Translate(effect.r, effect.g, effect.b, 0)
+	t := float64(effect.thickness)
+	for _, offset := range [][2]float64{{-t, 0}, {t, 0}, {0, -t}, {0, t}} {
+		ops := &colorm.DrawImageOptions{GeoM: geoM}
+		ops.GeoM.Translate(offset[0], offset[1])
+		colorm.DrawImage(screen, image, cm, ops)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// EffectStack public methods
+// -----------------------------------------------------------------------------
+
+// Add method pushes a new effect to the stack and returns it, so it can be
+// removed later.
+func (s *EffectStack) Add(effect *Effect) *Effect {
+	s.effects = append(s.effects, effect)
+	return effect
+}
+
+// Clear method removes all effects from the stack.
+func (s *EffectStack) Clear() {
+	s.effects = nil
+}
+
+// Draw method draws the image with the given geometry applying all effects
+// in the stack. Alpha is an additional opacity for the whole sprite.
+func (s *EffectStack) Draw(screen *ebiten.Image, image *ebiten.Image, geoM ebiten.GeoM, alpha float64) {
+	for _, effect := range s.effects {
+		if effect.kind == EffectOutline {
+			s.drawOutline(screen, image, geoM, effect, alpha)
+		}
+	}
+	cm := s.GetColorM()
+	cm.Scale(1, 1, 1, alpha)
+	ops := &colorm.DrawImageOptions{GeoM: geoM}
+	colorm.DrawImage(screen, image, cm, ops)
+}
+
+// GetColorM method returns the color matrix that combines all effects in the
+// stack.
+func (s *EffectStack) GetColorM() colorm.ColorM {
+	cm := colorm.ColorM{}
+	for _, effect := range s.effects {
+		effect.Apply(&cm)
+	}
+	return cm
+}
+
+func (s *EffectStack) GetEffects() []*Effect {
+	return s.effects
+}
+
+// IsEmpty method returns if there is not any effect in the stack.
+func (s *EffectStack) IsEmpty() bool {
+	return len(s.effects) == 0
+}
+
+// Remove method removes the given effect from the stack.
+func (s *EffectStack) Remove(effect *Effect) {
+	for i, e := range s.effects {
+		if e == effect {
+			s.effects = append(s.effects[:i], s.effects[i+1:]...)
+			return
+		}
+	}
+}
+
+// Update method advances all effects in the stack by the given elapsed time
+// in seconds and removes the ones that have expired.
+func (s *EffectStack) Update(dt float64) {
+	alive := s.effects[:0]
+	for _, effect := range s.effects {
+		effect.Update(dt)
+		if !effect.IsExpired() {
+			alive = append(alive, effect)
+		}
+	}
+	for i := len(alive); i < len(s.effects); i++ {
+		s.effects[i] = nil
+	}
+	s.effects = alive
+}
@@ -1,9 +1,13 @@
 package engine
 
+import "github.com/hajimehoshi/ebiten/v2"
+
 type IEntity interface {
 	IBase
 	GetHeight() int
+	GetInterpolatedPos(float64) (float64, float64)
 	GetPos() (float64, float64)
+	GetPrevPos() (float64, float64)
 	GetSize() (int, int)
 	GetWidth() int
 	GetX() float64
@@ -14,34 +18,96 @@ type IEntity interface {
 	SetWidth(int) IEntity
 	SetX(float64) IEntity
 	SetY(float64) IEntity
+	StorePrevPos()
 }
 
+// Entity structure defines an object with a position and a size. Position is
+// relative to the parent entity in the scene graph, or a world position when
+// the entity does not have any parent.
+//
+// The position at the beginning of the last simulation step is kept as the
+// previous position, so draws can interpolate between both. The previous
+// world position is kept too, so entities attached to a moving, rotated or
+// scaled parent are interpolated in world coordinates.
 type Entity struct {
 	*Base
-	x      float64
-	y      float64
-	height int
-	width  int
+	x          float64
+	y          float64
+	prevX      float64
+	prevY      float64
+	prevWorldX float64
+	prevWorldY float64
+	height     int
+	width      int
+	parent     ISceneNode
+	children   []ISceneNode
 }
 
 func NewEntity(name string, x, y float64, w, h int) *Entity {
 	return &Entity{
-		Base:   NewBase(name),
-		x:      x,
-		y:      y,
-		width:  w,
-		height: h,
+		Base:       NewBase(name),
+		x:          x,
+		y:          y,
+		prevX:      x,
+		prevY:      y,
+		prevWorldX: x,
+		prevWorldY: y,
+		width:      w,
+		height:     h,
 	}
 }
 
+func (e *Entity) setChildren(children []ISceneNode) {
+	e.children = children
+}
+
+func (e *Entity) setParent(parent ISceneNode) {
+	e.parent = parent
+}
+
+func (e *Entity) GetChildren() []ISceneNode {
+	return e.children
+}
+
 func (e *Entity) GetHeight() int {
 	return e.height
 }
 
+// GetLocalTransform method returns the transform from the entity local
+// coordinates to its parent coordinates, which is only a translation to the
+// entity position.
+func (e *Entity) GetLocalTransform() ebiten.GeoM {
+	geoM := ebiten.GeoM{}
+	geoM.Translate(e.x, e.y)
+	return geoM
+}
+
+// GetInterpolatedPos method returns the position between the previous and the
+// current position for the given interpolation alpha, between 0 and 1.
+func (e *Entity) GetInterpolatedPos(alpha float64) (float64, float64) {
+	return e.prevX + (e.x-e.prevX)*alpha, e.prevY + (e.y-e.prevY)*alpha
+}
+
+func (e *Entity) GetParent() ISceneNode {
+	return e.parent
+}
+
 func (e *Entity) GetPos() (float64, float64) {
 	return e.x, e.y
 }
 
+// GetPrevPos method returns the position stored at the beginning of the last
+// simulation step.
+func (e *Entity) GetPrevPos() (float64, float64) {
+	return e.prevX, e.prevY
+}
+
+// GetPrevWorldPos method returns the world position stored at the beginning
+// of the last simulation step.
+func (e *Entity) GetPrevWorldPos() (float64, float64) {
+	return e.prevWorldX, e.prevWorldY
+}
+
 func (e *Entity) GetSize() (int, int) {
 	return e.width, e.height
 }
@@ -88,4 +154,23 @@ func (e *Entity) SetY(y float64) IEntity {
 	return e
 }
 
+// StorePrevPos method stores the current position, local and world, as the
+// previous position, for the entity and all its children. It has to be
+// called at the beginning of every simulation step, and after teleporting
+// the entity, so it is not interpolated from the old position.
+func (e *Entity) StorePrevPos() {
+	e.prevX, e.prevY = e.x, e.y
+	// the entity origin is placed at its position in the parent coordinates
+	// by any local transform.
+	e.prevWorldX, e.prevWorldY = e.x, e.y
+	if e.parent != nil {
+		geoM := GetWorldTransform(e.parent)
+		e.prevWorldX, e.prevWorldY = geoM.Apply(e.x, e.y)
+	}
+	for _, child := range e.children {
+		child.StorePrevPos()
+	}
+}
+
 var _ IEntity = (*Entity)(nil)
+var _ ISceneNode = (*Entity)(nil)
@@ -0,0 +1,63 @@
+package engine
+
+// EventHandler type defines a function called when an event is published in
+// a topic the handler is subscribed to.
+type EventHandler func(topic string, payload any)
+
+// eventSubscription structure keeps a handler with the ID returned when it
+// was subscribed, used to unsubscribe it.
+type eventSubscription struct {
+	id      int
+	handler EventHandler
+}
+
+// EventBus structure defines a synchronous publish and subscribe channel for
+// game events. Handlers are called in the order they were subscribed, in the
+// same goroutine that publishes the event.
+type EventBus struct {
+	handlers map[string][]eventSubscription
+	nextID   int
+}
+
+// NewEventBus function creates a new EventBus instance.
+func NewEventBus() *EventBus {
+	return &EventBus{
+		handlers: make(map[string][]eventSubscription),
+	}
+}
+
+// -----------------------------------------------------------------------------
+// EventBus public methods
+// -----------------------------------------------------------------------------
+
+// Publish method calls all handlers subscribed to the topic with the given
+// payload. Handlers subscribed or unsubscribed by other handlers take effect
+// for the next event.
+func (b *EventBus) Publish(topic string, payload any) {
+	subscriptions := append([]eventSubscription(nil), b.handlers[topic]...)
+	for _, subscription := range subscriptions {
+		subscription.handler(topic, payload)
+	}
+}
+
+// Subscribe method adds the handler to the topic, and returns the ID used to
+// unsubscribe it.
+func (b *EventBus) Subscribe(topic string, handler EventHandler) int {
+	b.nextID++
+	b.handlers[topic] = append(b.handlers[topic], eventSubscription{id: b.nextID, handler: handler})
+	return b.nextID
+}
+
+// Unsubscribe method removes the handler with the given ID from the topic.
+func (b *EventBus) Unsubscribe(topic string, id int) {
+	subscriptions := b.handlers[topic]
+	for i, subscription := range subscriptions {
+		if subscription.id == id {
+			b.handlers[topic] = append(subscriptions[:i:i], subscriptions[i+1:]...)
+			break
+		}
+	}
+	if len(b.handlers[topic]) == 0 {
+		delete(b.handlers, topic)
+	}
+}
@@ -0,0 +1,42 @@
+package engine
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// Flip type defines how a sprite frame is mirrored when it is drawn.
+type Flip int
+
+const (
+	FlipNone       Flip = 0
+	FlipHorizontal Flip = 1 << 0
+	FlipVertical   Flip = 1 << 1
+	FlipBoth            = FlipHorizontal | FlipVertical
+)
+
+// ApplyFlip function mirrors the given geometry matrix around the point
+// (pivotX, pivotY), which is given in the sprite local coordinates. It has to
+// be applied before any scale, rotation or translation.
+func ApplyFlip(geoM *ebiten.GeoM, flip Flip, pivotX, pivotY float64) {
+	if flip == FlipNone {
+		return
+	}
+	scaleX, scaleY := 1.0, 1.0
+	if flip.IsHorizontal() {
+		scaleX = -1
+	}
+	if flip.IsVertical() {
+		scaleY = -1
+	}
+	geoM.Translate(-pivotX, -pivotY)
+	geoM.Scale(scaleX, scaleY)
+	geoM.Translate(pivotX, pivotY)
+}
+
+// IsHorizontal method returns if the flip mirrors the horizontal axis.
+func (f Flip) IsHorizontal() bool {
+	return f&FlipHorizontal != 0
+}
+
+// IsVertical method returns if the flip mirrors the vertical axis.
+func (f Flip) IsVertical() bool {
+	return f&FlipVertical != 0
+}
@@ -4,88 +4,228 @@ import (
 	"fmt"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
 type IGridActor interface {
+	IsMoving() bool
 	MoveUpdate(string, float64, float64) error
+	UpdateMove(float64)
 }
 
+// GridActor structure defines an actor that moves from tile to tile. Speed is
+// the distance for every move, usually the tile size, and the move is
+// animated over the move duration.
+//
+// Input received while the actor is moving is queued, so the next move starts
+// as soon as the current one completes, or rejected if queueing is disabled.
+//
+// Moves are animated with a tween instead of MoveAndCollide, so when a
+// collision world is set, moves into a cell blocked by a solid collider or a
+// solid tile are rejected before they start.
 type GridActor struct {
 	*Actor
+	moveDuration  float64
+	moving        bool
+	move          *TweenGroup
+	queueInput    bool
+	queued        string
+	width, height float64
+	fromX, fromY  float64
+	tilegrid      *TileGrid
+	collision     *CollisionWorld
 }
 
 func NewGridActor(name string, spritesheet *SpriteSheet, x, y float64) *GridActor {
-	return &GridActor{
-		Actor: NewActor(name, spritesheet, x, y),
+	actor := &GridActor{
+		Actor:        NewActor(name, spritesheet, x, y),
+		moveDuration: 0.2,
+		queueInput:   true,
 	}
+	actor.setMoving(false)
+	return actor
 }
 
-func (a *GridActor) MoveUpdate(moveto string, width, height float64) error {
-	x, y := a.GetPos()
-	w := float64(a.GetSpriteSheet().Width) * a.GetScale()
-	h := float64(a.GetSpriteSheet().Height) * a.GetScale()
+// -----------------------------------------------------------------------------
+// GridActor private methods
+// -----------------------------------------------------------------------------
+
+// completeMove method is called when the move animation completes. It
+// updates tile grid occupancy and starts any queued move.
+func (a *GridActor) completeMove() {
+	a.move = nil
+	a.setMoving(false)
 	a.SetDx(0.0)
 	a.SetDy(0.0)
+	if a.tilegrid != nil {
+		fromX, fromY := a.tilegrid.GetTilePosFromScreenPos(a.fromX, a.fromY)
+		toX, toY := a.tilegrid.GetTilePosFromScreenPos(a.GetPos())
+		from, to := GetKeyIntToString(fromX, fromY), GetKeyIntToString(toX, toY)
+		if from != to {
+			a.tilegrid.MoveTileFromTo(from, to, a)
+		}
+	}
+	if queued := a.queued; queued != "" {
+		a.queued = ""
+		a.MoveUpdate(queued, a.width, a.height)
+	}
+}
+
+// isBlocked method returns if the actor, moved by the given delta, overlaps
+// any solid collider or solid tile in the collision world.
+func (a *GridActor) isBlocked(dx, dy float64) bool {
+	if a.collision == nil {
+		return false
+	}
+	shape := a.GetShape().Transform(dx, dy, 0, 1)
+	for _, other := range a.collision.OverlapShape(shape, a.GetCollisionMask(), a) {
+		if other.IsSolid() && CanCollide(a, other) {
+			return true
+		}
+	}
+	if grid := a.collision.GetTileGrid(); grid != nil && grid.GetCollisionLayer()&a.GetCollisionMask() != 0 {
+		return len(grid.GetSolidTiles(shape.GetBounds())) != 0
+	}
+	return false
+}
+
+// setMoving method sets if the actor is moving, so the walk animation is
+// played only during motion. The sprite sheet is only paused without an
+// animator, because an animator drives the sheet with the "moving"
+// parameter.
+func (a *GridActor) setMoving(moving bool) {
+	a.moving = moving
+	if a.animator != nil {
+		a.animator.SetParam("moving", moving)
+		if a.spritesheet != nil {
+			a.spritesheet.SetPaused(false)
+		}
+	} else if a.spritesheet != nil {
+		a.spritesheet.SetPaused(!moving)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// GridActor public methods
+// -----------------------------------------------------------------------------
+
+func (a *GridActor) GetCollisionWorld() *CollisionWorld {
+	return a.collision
+}
+
+func (a *GridActor) GetMoveDuration() float64 {
+	return a.moveDuration
+}
+
+func (a *GridActor) GetTileGrid() *TileGrid {
+	return a.tilegrid
+}
+
+// IsMoving method returns if the actor is moving between two tiles.
+func (a *GridActor) IsMoving() bool {
+	return a.moving
+}
+
+// IsQueueInput method returns if input received while moving is queued.
+func (a *GridActor) IsQueueInput() bool {
+	return a.queueInput
+}
+
+// MoveUpdate method starts moving the actor one tile in the given direction,
+// if the target is inside the given tilemap boundary and it is not blocked in
+// the collision world.
+func (a *GridActor) MoveUpdate(moveto string, width, height float64) error {
+	var dx, dy float64
 	switch moveto {
 	case "right":
-		if IsInsideTilemapBoundary(x+a.GetSpeed(), y, width, height, w, h) {
-			a.SetDx(a.GetSpeed())
-		}
-		a.GetSpriteSheet().UpdateFrameType("right")
+		dx = a.GetSpeed()
 	case "left":
-		if IsInsideTilemapBoundary(x-a.GetSpeed(), y, width, height, w, h) {
-			a.SetDx(-a.GetSpeed())
-		}
-		a.GetSpriteSheet().UpdateFrameType("left")
+		dx = -a.GetSpeed()
 	case "up":
-		if IsInsideTilemapBoundary(x, y-a.GetSpeed(), width, height, w, h) {
-			a.SetDy(-a.GetSpeed())
-		}
-		a.GetSpriteSheet().UpdateFrameType("up")
+		dy = -a.GetSpeed()
 	case "down":
-		if IsInsideTilemapBoundary(x, y+a.GetSpeed(), width, height, w, h) {
-			a.SetDy(a.GetSpeed())
-		}
-		a.GetSpriteSheet().UpdateFrameType("down")
+		dy = a.GetSpeed()
 	default:
 		return fmt.Errorf("unknown movement direction %s", moveto)
 	}
-	a.SetPos(x+a.GetDx(), y+a.GetDy())
+	if a.moving {
+		if a.queueInput {
+			a.queued = moveto
+		}
+		return nil
+	}
+	a.setDirection(moveto)
+	a.width, a.height = width, height
+	if !a.isInsideBoundary(dx, dy, width, height) || a.isBlocked(dx, dy) {
+		a.UpdateAnimation(0)
+		return nil
+	}
+	x, y := a.GetPos()
+	a.fromX, a.fromY = x, y
+	a.SetDx(dx)
+	a.SetDy(dy)
+	a.setMoving(true)
+	a.move = TweenPosTo(a, x+dx, y+dy, a.moveDuration).OnComplete(a.completeMove)
+	a.UpdateAnimation(0)
 	return nil
 }
 
-func (a *GridActor) Update(args ...any) error {
-	tilemapWidthInPixels := args[0].(float64)
-	tilemapHeightInPixels := args[1].(float64)
-	x, y := a.GetPos()
-	w := float64(a.GetSpriteSheet().Width) * a.GetScale()
-	h := float64(a.GetSpriteSheet().Height) * a.GetScale()
-	a.SetDx(0.0)
-	a.SetDy(0.0)
-	if inpututil.IsKeyJustPressed(ebiten.KeyRight) {
-		if IsInsideTilemapBoundary(x+a.GetSpeed(), y, tilemapWidthInPixels, tilemapHeightInPixels, w, h) {
-			a.SetDx(a.GetSpeed())
-		}
-		a.GetSpriteSheet().UpdateFrameType("right")
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyLeft) {
-		if IsInsideTilemapBoundary(x-a.GetSpeed(), y, tilemapWidthInPixels, tilemapHeightInPixels, w, h) {
-			a.SetDx(-a.GetSpeed())
-		}
-		a.GetSpriteSheet().UpdateFrameType("left")
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyUp) {
-		if IsInsideTilemapBoundary(x, y-a.GetSpeed(), tilemapWidthInPixels, tilemapHeightInPixels, w, h) {
-			a.SetDy(-a.GetSpeed())
-		}
-		a.GetSpriteSheet().UpdateFrameType("up")
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyDown) {
-		if IsInsideTilemapBoundary(x, y+a.GetSpeed(), tilemapWidthInPixels, tilemapHeightInPixels, w, h) {
-			a.SetDy(a.GetSpeed())
-		}
-		a.GetSpriteSheet().UpdateFrameType("down")
+// SetCollisionWorld method sets the collision world checked before every move,
+// or nil to move without collisions.
+func (a *GridActor) SetCollisionWorld(world *CollisionWorld) *GridActor {
+	a.collision = world
+	return a
+}
+
+// SetMoveDuration method sets the time, in seconds, the actor takes to move
+// from one tile to the next one.
+func (a *GridActor) SetMoveDuration(duration float64) *GridActor {
+	a.moveDuration = duration
+	return a
+}
+
+// SetQueueInput method sets if input received while moving is queued for the
+// next move or rejected.
+func (a *GridActor) SetQueueInput(queue bool) *GridActor {
+	a.queueInput = queue
+	return a
+}
+
+// SetTileGrid method sets the tile grid that is notified when the actor
+// completes a move, so tile occupancy is updated.
+func (a *GridActor) SetTileGrid(tilegrid *TileGrid) *GridActor {
+	a.tilegrid = tilegrid
+	return a
+}
+
+// Update method starts a move with the arrow keys, inside the context world
+// boundary, and advances the move animation and visual effects.
+func (a *GridActor) Update(ctx *UpdateContext) error {
+	tilemapWidthInPixels, tilemapHeightInPixels := ctx.GetBoundsSize()
+	var err error
+	if ctx.Input.IsKeyJustPressed(ebiten.KeyRight) {
+		err = a.MoveUpdate("right", tilemapWidthInPixels, tilemapHeightInPixels)
+	} else if ctx.Input.IsKeyJustPressed(ebiten.KeyLeft) {
+		err = a.MoveUpdate("left", tilemapWidthInPixels, tilemapHeightInPixels)
+	} else if ctx.Input.IsKeyJustPressed(ebiten.KeyUp) {
+		err = a.MoveUpdate("up", tilemapWidthInPixels, tilemapHeightInPixels)
+	} else if ctx.Input.IsKeyJustPressed(ebiten.KeyDown) {
+		err = a.MoveUpdate("down", tilemapWidthInPixels, tilemapHeightInPixels)
+	}
+	if err != nil {
+		return err
 	}
-	a.SetPos(x+a.GetDx(), y+a.GetDy())
+	a.UpdateMove(ctx.Delta)
+	a.UpdateEffects(ctx.Delta)
 	return nil
 }
 
+// UpdateMove method advances the move animation and the sprite animation by
+// the given elapsed time in seconds. It has to be called every update.
+func (a *GridActor) UpdateMove(dt float64) {
+	if a.move != nil {
+		a.move.Update(dt)
+	}
+	a.UpdateAnimation(dt)
+}
+
 var _ IGridActor = (*GridActor)(nil)
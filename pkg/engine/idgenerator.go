@@ -0,0 +1,170 @@
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// IIDGenerator defines the interface for objects that create unique
+// identifiers for new Base instances.
+type IIDGenerator interface {
+	// NextID returns a new unique identifier.
+	NextID() string
+}
+
+// IIDRestorer defines the interface for ID generators that have to know
+// about identifiers restored from a saved state, so they never generate
+// them again.
+type IIDRestorer interface {
+	// RestoreID notifies the generator that the given ID is in use.
+	RestoreID(string)
+}
+
+// TimeIDGenerator is the default ID generator. IDs combine the wall-clock
+// time, an atomic counter and a random number, so they are unique across
+// program executions but different every run.
+type TimeIDGenerator struct{}
+
+// NewTimeIDGenerator creates and returns a new instance of the
+// TimeIDGenerator struct.
+func NewTimeIDGenerator() *TimeIDGenerator {
+	return &TimeIDGenerator{}
+}
+
+// NextID creates a unique identifier string.
+//
+// The generated ID combines three components:
+// 1. A timestamp (in milliseconds) to ensure uniqueness across time.
+// 2. An atomic counter to guarantee uniqueness even within the same millisecond.
+// 3. A random number for additional entropy.
+//
+// The resulting format is: "timestamp-counter-randomNum"
+// where timestamp is in milliseconds, counter is an incrementing integer,
+// and randomNum is a three-digit random number.
+//
+// This method is safe for concurrent use.
+//
+// Returns:
+//   - string: A unique identifier in the format "timestamp-counter-randomNum".
+func (g *TimeIDGenerator) NextID() string {
+	timestamp := time.Now().UnixNano() / int64(time.Millisecond)
+	randomNum := rng.Intn(1000)
+	count := atomic.AddUint64(&counter, 1)
+	return fmt.Sprintf("%d-%d-%03d", timestamp, count, randomNum)
+}
+
+// SequentialIDGenerator creates deterministic identifiers made of a prefix
+// and an incrementing counter, like "entity-1", "entity-2", and so on. The
+// same sequence of creations always produces the same IDs, so they can be
+// used in save files, replays and tests.
+type SequentialIDGenerator struct {
+	prefix  string
+	counter uint64
+}
+
+// NewSequentialIDGenerator creates and returns a new instance of the
+// SequentialIDGenerator struct. The first generated ID uses the counter one.
+func NewSequentialIDGenerator(prefix string) *SequentialIDGenerator {
+	return &SequentialIDGenerator{
+		prefix: prefix,
+	}
+}
+
+// NextID returns the next identifier in the sequence.
+//
+// This method is safe for concurrent use.
+func (g *SequentialIDGenerator) NextID() string {
+	count := atomic.AddUint64(&g.counter, 1)
+	return fmt.Sprintf("%s-%d", g.prefix, count)
+}
+
+// RestoreID moves the sequence past the counter in the given ID, if the ID
+// belongs to this generator, so restored IDs are never generated again.
+func (g *SequentialIDGenerator) RestoreID(id string) {
+	value, ok := strings.CutPrefix(id, g.prefix+"-")
+	if !ok {
+		return
+	}
+	count, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return
+	}
+	for {
+		current := atomic.LoadUint64(&g.counter)
+		if count <= current || atomic.CompareAndSwapUint64(&g.counter, current, count) {
+			return
+		}
+	}
+}
+
+// SeededIDGenerator creates identifiers made of an incrementing counter and
+// a random number from a generator with the given seed, keeping the format
+// of the default generator without the timestamp. The same seed always
+// produces the same IDs.
+type SeededIDGenerator struct {
+	mu      sync.Mutex
+	rng     *rand.Rand
+	counter uint64
+}
+
+// NewSeededIDGenerator creates and returns a new instance of the
+// SeededIDGenerator struct.
+func NewSeededIDGenerator(seed int64) *SeededIDGenerator {
+	return &SeededIDGenerator{
+		rng: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// NextID returns the next identifier in the format "counter-randomNum".
+//
+// This method is safe for concurrent use.
+func (g *SeededIDGenerator) NextID() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.counter++
+	return fmt.Sprintf("%d-%03d", g.counter, g.rng.Intn(1000))
+}
+
+// RestoreID moves the counter past the counter in the given ID, so restored
+// IDs are never generated again.
+func (g *SeededIDGenerator) RestoreID(id string) {
+	value, _, _ := strings.Cut(id, "-")
+	count, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.counter = max(g.counter, count)
+}
+
+// GetIDGenerator returns the ID generator used for new Base instances.
+func GetIDGenerator() IIDGenerator {
+	return idGenerator
+}
+
+// SetIDGenerator sets the ID generator used for new Base instances and
+// returns the previous one, so it can be restored. It should be called
+// before any object is created, usually when the game starts or at the
+// beginning of a test.
+//
+// Example usage:
+//
+//	previous := SetIDGenerator(NewSequentialIDGenerator("entity"))
+//	defer SetIDGenerator(previous)
+func SetIDGenerator(generator IIDGenerator) IIDGenerator {
+	previous := idGenerator
+	idGenerator = generator
+	return previous
+}
+
+var _ IIDGenerator = (*TimeIDGenerator)(nil)
+var _ IIDGenerator = (*SequentialIDGenerator)(nil)
+var _ IIDGenerator = (*SeededIDGenerator)(nil)
+var _ IIDRestorer = (*SequentialIDGenerator)(nil)
+var _ IIDRestorer = (*SeededIDGenerator)(nil)
@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// IInput interface defines the input state available for updates. It allows
+// input to be replaced, for example to replay recorded input or to drive
+// objects from tests.
+type IInput interface {
+	CursorPosition() (int, int)
+	IsKeyJustPressed(ebiten.Key) bool
+	IsKeyPressed(ebiten.Key) bool
+	IsMouseButtonJustPressed(ebiten.MouseButton) bool
+}
+
+// EbitenInput structure defines the input state read from ebiten for the
+// current tick.
+type EbitenInput struct{}
+
+// NewEbitenInput function creates a new EbitenInput instance.
+func NewEbitenInput() *EbitenInput {
+	return &EbitenInput{}
+}
+
+// -----------------------------------------------------------------------------
+// EbitenInput public methods
+// -----------------------------------------------------------------------------
+
+// CursorPosition method returns the cursor position in screen coordinates.
+func (i *EbitenInput) CursorPosition() (int, int) {
+	return ebiten.CursorPosition()
+}
+
+// IsKeyJustPressed method returns if the key was pressed in the current tick.
+func (i *EbitenInput) IsKeyJustPressed(key ebiten.Key) bool {
+	return inpututil.IsKeyJustPressed(key)
+}
+
+// IsKeyPressed method returns if the key is being pressed.
+func (i *EbitenInput) IsKeyPressed(key ebiten.Key) bool {
+	return ebiten.IsKeyPressed(key)
+}
+
+// IsMouseButtonJustPressed method returns if the mouse button was pressed in
+// the current tick.
+func (i *EbitenInput) IsMouseButtonJustPressed(button ebiten.MouseButton) bool {
+	return inpututil.IsMouseButtonJustPressed(button)
+}
+
+var _ IInput = (*EbitenInput)(nil)
@@ -2,7 +2,6 @@ package engine
 
 import (
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
 type KeyBindingFunc func()
@@ -60,24 +59,25 @@ func (k *KeyboardHandler) GetKeyBindingsForKey(mainkey ebiten.Key, modifiers []e
 	return nil
 }
 
-func isBindingPressed(binding *KeyBinding) bool {
-	if !inpututil.IsKeyJustPressed(binding.MainKey) {
+func isBindingPressed(binding *KeyBinding, input IInput) bool {
+	if !input.IsKeyJustPressed(binding.MainKey) {
 		return false
 	}
 	for _, modifier := range binding.Modifiers {
-		if !inpututil.IsKeyJustPressed(modifier) {
+		if !input.IsKeyJustPressed(modifier) {
 			return false
 		}
 	}
 	return true
 }
 
-func (k *KeyboardHandler) Update(args ...any) {
+func (k *KeyboardHandler) Update(ctx *UpdateContext) error {
 	for keybinding, bindings := range k.keybindings {
-		if isBindingPressed(keybinding) {
+		if isBindingPressed(keybinding, ctx.Input) {
 			for _, f := range bindings {
 				f()
 			}
 		}
 	}
+	return nil
 }
@@ -6,12 +6,22 @@ import (
 	"strings"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 	"github.com/jrecuero/ebiplay/pkg/tools"
 	"golang.org/x/image/font/basicfont"
 )
 
+const (
+	// menuTopY is the vertical position for the first menu item.
+	menuTopY = 60
+	// menuLineHeight is the vertical space for every menu item.
+	menuLineHeight = 10
+	// menuHighlightDuration is the time, in seconds, the selection highlight
+	// takes to move to a new menu item.
+	menuHighlightDuration = 0.1
+)
+
 type Menu struct {
 	*Base
 	x, y          float64
@@ -21,6 +31,8 @@ type Menu struct {
 	menuItemIndex int
 	scroller      *Scroller
 	parent        *Menu
+	highlightY    float64
+	highlight     *Tween
 }
 
 // NewTopMenu function creates a new Menu instance.
@@ -125,6 +137,21 @@ func (m *Menu) execute(args ...any) {
 	}
 }
 
+// getSelectionY method returns the vertical position for the selected menu
+// item, based on the items currently displayed by the scroller.
+func (m *Menu) getSelectionY() float64 {
+	return float64(menuTopY + (m.menuItemIndex-m.scroller.StartSelection)*menuLineHeight)
+}
+
+// moveHighlight method starts animating the selection highlight to the
+// selected menu item.
+func (m *Menu) moveHighlight() {
+	m.scroller.Update(m.menuItemIndex)
+	m.highlight = NewTween(m.highlightY, m.getSelectionY(), menuHighlightDuration, func(y float64) {
+		m.highlightY = y
+	}).SetEase(tools.EaseOutQuad)
+}
+
 func (m *Menu) nextMenuItem() {
 	index := m.menuItemIndex
 	for index < (len(m.menuItems) - 1) {
@@ -205,9 +232,14 @@ func (m *Menu) Draw(screen *ebiten.Image) {
 	//    text.Draw(screen, line, face, 10, y, color.White)
 	//}
 	m.scroller.Update(m.menuItemIndex)
+	if m.highlight == nil {
+		m.highlightY = m.getSelectionY()
+	}
+	width := float32(len(m.getMenuItemLabel(m.menuItemIndex))*face.Advance + 4)
+	vector.DrawFilledRect(screen, 8, float32(m.highlightY)-menuLineHeight, width, menuLineHeight+2, color.White, false)
 	m.scroller.CreateIter()
 	for i := 0; m.scroller.IterHasNext(); i++ {
-		j := 60 + i*10
+		j := menuTopY + i*menuLineHeight
 		index, y := m.scroller.IterGetNext()
 		_ = y
 		selection := m.getMenuItemLabel(index)
@@ -296,6 +328,7 @@ func (m *Menu) Refresh() {
 func (m *Menu) SetSelectionToIndex(index int) error {
 	if index < len(m.menuItems) {
 		m.menuItemIndex = index
+		m.moveHighlight()
 		return nil
 	}
 	return fmt.Errorf("Index %d out of range for menu", index)
@@ -307,6 +340,7 @@ func (m *Menu) SetSelectionToLabel(label string) error {
 	for index, menuLabel := range m.menuLabels {
 		if menuLabel == label {
 			m.menuItemIndex = index
+			m.moveHighlight()
 			return nil
 		}
 	}
@@ -316,10 +350,18 @@ func (m *Menu) SetSelectionToLabel(label string) error {
 // Update method executes all listbox functionality every tick time. Keyboard
 // inut is scanned in order to move the selection index and proceed to select
 // any option.
-func (m *Menu) Update() {
-	if inpututil.IsKeyJustPressed(ebiten.KeyDown) {
+func (m *Menu) Update(ctx *UpdateContext) error {
+	index := m.menuItemIndex
+	if ctx.Input.IsKeyJustPressed(ebiten.KeyDown) {
 		m.nextMenuItem()
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyUp) {
+	} else if ctx.Input.IsKeyJustPressed(ebiten.KeyUp) {
 		m.prevMenuItem()
 	}
+	if index != m.menuItemIndex {
+		m.moveHighlight()
+	}
+	if m.highlight != nil {
+		m.highlight.Update(ctx.Delta)
+	}
+	return nil
 }
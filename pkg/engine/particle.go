@@ -0,0 +1,313 @@
+package engine
+
+import (
+	"encoding/json"
+	"image/color"
+	"log"
+	"math"
+	"math/rand"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jrecuero/ebiplay/pkg/tools"
+)
+
+// particleImage is the default image used for particles without a sprite
+// sheet. It is a white pixel scaled to the particle size and tinted with the
+// particle color.
+var particleImage *ebiten.Image
+
+// getParticleImage function returns the default particle image, creating it
+// the first time it is required.
+func getParticleImage() *ebiten.Image {
+	if particleImage == nil {
+		particleImage = ebiten.NewImage(1, 1)
+		particleImage.Fill(color.White)
+	}
+	return particleImage
+}
+
+// ParticleEmitterConfig structure defines how particles are spawned and how
+// they change over their life. Times are in seconds, distances in pixels and
+// angles in degrees, where zero points right and 90 points down. Colors are
+// RGBA values between 0 and 1.
+type ParticleEmitterConfig struct {
+	MaxParticles int        `json:"maxParticles"`
+	Rate         float64    `json:"rate"`
+	LifetimeMin  float64    `json:"lifetimeMin"`
+	LifetimeMax  float64    `json:"lifetimeMax"`
+	SpeedMin     float64    `json:"speedMin"`
+	SpeedMax     float64    `json:"speedMax"`
+	Angle        float64    `json:"angle"`
+	Spread       float64    `json:"spread"`
+	GravityX     float64    `json:"gravityX"`
+	GravityY     float64    `json:"gravityY"`
+	Size         float64    `json:"size"`
+	StartScale   float64    `json:"startScale"`
+	EndScale     float64    `json:"endScale"`
+	StartColor   [4]float64 `json:"startColor"`
+	EndColor     [4]float64 `json:"endColor"`
+	FrameType    string     `json:"frameType"`
+}
+
+// NewParticleEmitterConfig function creates a new ParticleEmitterConfig
+// instance with default values: white particles of one pixel that live one
+// second and fade out.
+func NewParticleEmitterConfig() *ParticleEmitterConfig {
+	return &ParticleEmitterConfig{
+		MaxParticles: 100,
+		Rate:         10,
+		LifetimeMin:  1,
+		LifetimeMax:  1,
+		SpeedMin:     20,
+		SpeedMax:     20,
+		Spread:       360,
+		Size:         1,
+		StartScale:   1,
+		EndScale:     1,
+		StartColor:   [4]float64{1, 1, 1, 1},
+		EndColor:     [4]float64{1, 1, 1, 0},
+	}
+}
+
+// LoadParticlePresetsJSON function loads emitter configurations from a JSON
+// file, which contains an object where every key is the preset name and the
+// value is the emitter configuration. Fields not present in a preset take
+// default values.
+func LoadParticlePresetsJSON(path string) map[string]*ParticleEmitterConfig {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("can not load particle presets JSON file %s: %s", path, err)
+	}
+	raw := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(content, &raw); err != nil {
+		log.Fatalf("can not unmarshal particle presets JSON file %s: %s", path, err)
+	}
+	presets := make(map[string]*ParticleEmitterConfig, len(raw))
+	for name, data := range raw {
+		config := NewParticleEmitterConfig()
+		if err := json.Unmarshal(data, config); err != nil {
+			log.Fatalf("can not unmarshal particle preset %s in %s: %s", name, path, err)
+		}
+		presets[name] = config
+	}
+	return presets
+}
+
+// particle structure defines every particle in the emitter pool.
+type particle struct {
+	x, y   float64
+	vx, vy float64
+	age    float64
+	life   float64
+	alive  bool
+}
+
+// ParticleEmitter structure defines a particle emitter that spawns particles
+// in bursts or continuously at its position. Particles are stored in a pool
+// allocated when the emitter is created, so no memory is allocated while it
+// runs.
+type ParticleEmitter struct {
+	*Entity
+	config      *ParticleEmitterConfig
+	particles   []particle
+	free        []int
+	emitting    bool
+	accumulator float64
+	rng         *rand.Rand
+	spritesheet *SpriteSheet
+	renderLayer int
+	ops         ebiten.DrawImageOptions
+}
+
+// minParticleLife is the minimum particle life in seconds, so particles
+// configured without lifetime die in the next update instead of dividing
+// by zero.
+const minParticleLife = 1e-3
+
+// NewParticleEmitter function creates a new ParticleEmitter instance at the
+// given position.
+func NewParticleEmitter(name string, config *ParticleEmitterConfig, x, y float64) *ParticleEmitter {
+	emitter := &ParticleEmitter{
+		Entity:      NewEntity(name, x, y, 0, 0),
+		config:      config,
+		particles:   make([]particle, config.MaxParticles),
+		free:        make([]int, config.MaxParticles),
+		rng:         tools.RandomRing,
+		renderLayer: RenderLayerWorld,
+	}
+	for i := range emitter.free {
+		// pop from the end, so the first particles in the pool are used first.
+		emitter.free[i] = config.MaxParticles - 1 - i
+	}
+	return emitter
+}
+
+// -----------------------------------------------------------------------------
+// ParticleEmitter private methods
+// -----------------------------------------------------------------------------
+
+// randomRange method returns a random value between min and max.
+func (e *ParticleEmitter) randomRange(min, max float64) float64 {
+	if max <= min {
+		return min
+	}
+	return min + e.rng.Float64()*(max-min)
+}
+
+// spawn method takes a particle from the pool and initializes it. It returns
+// false if the pool is exhausted.
+func (e *ParticleEmitter) spawn() bool {
+	if len(e.free) == 0 {
+		return false
+	}
+	index := e.free[len(e.free)-1]
+	e.free = e.free[:len(e.free)-1]
+	angle := e.config.Angle + e.randomRange(-e.config.Spread/2, e.config.Spread/2)
+	radians := angle * math.Pi / 180
+	speed := e.randomRange(e.config.SpeedMin, e.config.SpeedMax)
+	// particles live in world coordinates, so they do not follow the emitter
+	// once they are spawned.
+	x, y := GetWorldPos(e)
+	e.particles[index] = particle{
+		x:     x,
+		y:     y,
+		vx:    math.Cos(radians) * speed,
+		vy:    math.Sin(radians) * speed,
+		life:  math.Max(e.randomRange(e.config.LifetimeMin, e.config.LifetimeMax), minParticleLife),
+		alive: true,
+	}
+	return true
+}
+
+// -----------------------------------------------------------------------------
+// ParticleEmitter public methods
+// -----------------------------------------------------------------------------
+
+// Burst method spawns the given number of particles at once.
+func (e *ParticleEmitter) Burst(count int) {
+	for i := 0; i < count; i++ {
+		if !e.spawn() {
+			return
+		}
+	}
+}
+
+// Draw method draws all alive particles relative to the camera.
+func (e *ParticleEmitter) Draw(screen *ebiten.Image, camera *Camera) {
+	c := e.config
+	for i := range e.particles {
+		p := &e.particles[i]
+		if !p.alive {
+			continue
+		}
+		t := p.age / p.life
+		img := getParticleImage()
+		size := c.Size
+		if e.spritesheet != nil && c.FrameType != "" {
+			index := int(t * float64(e.spritesheet.GetFrameCount(c.FrameType)))
+			img = e.spritesheet.GetFrameAt(c.FrameType, index)
+			size = 1
+		}
+		w, h := img.Bounds().Dx(), img.Bounds().Dy()
+		scale := size * (c.StartScale + (c.EndScale-c.StartScale)*t)
+		e.ops.GeoM.Reset()
+		e.ops.ColorScale.Reset()
+		e.ops.GeoM.Translate(-float64(w)/2, -float64(h)/2)
+		e.ops.GeoM.Scale(scale, scale)
+		e.ops.GeoM.Translate(p.x, p.y)
+		camera.Apply(&e.ops.GeoM)
+		var rgba [4]float32
+		for j := range rgba {
+			rgba[j] = float32(c.StartColor[j] + (c.EndColor[j]-c.StartColor[j])*t)
+		}
+		// color scale uses premultiplied alpha.
+		e.ops.ColorScale.Scale(rgba[0]*rgba[3], rgba[1]*rgba[3], rgba[2]*rgba[3], rgba[3])
+		screen.DrawImage(img, &e.ops)
+	}
+}
+
+// GetAliveCount method returns the number of particles alive.
+func (e *ParticleEmitter) GetAliveCount() int {
+	return len(e.particles) - len(e.free)
+}
+
+func (e *ParticleEmitter) GetConfig() *ParticleEmitterConfig {
+	return e.config
+}
+
+func (e *ParticleEmitter) GetRenderLayer() int {
+	return e.renderLayer
+}
+
+// IsEmitting method returns if the emitter is spawning particles
+// continuously.
+func (e *ParticleEmitter) IsEmitting() bool {
+	return e.emitting
+}
+
+// SetRandom method sets the random number generator used to spawn particles.
+func (e *ParticleEmitter) SetRandom(rng *rand.Rand) *ParticleEmitter {
+	e.rng = rng
+	return e
+}
+
+func (e *ParticleEmitter) SetRenderLayer(layer int) *ParticleEmitter {
+	e.renderLayer = layer
+	return e
+}
+
+// SetSpriteSheet method sets the sprite sheet used to draw particles. Frames
+// for the configuration frame type are played over the particle life.
+func (e *ParticleEmitter) SetSpriteSheet(spritesheet *SpriteSheet) *ParticleEmitter {
+	e.spritesheet = spritesheet
+	return e
+}
+
+// Start method starts spawning particles continuously at the configuration
+// rate.
+func (e *ParticleEmitter) Start() {
+	e.emitting = true
+}
+
+// Stop method stops spawning particles. Alive particles keep updating until
+// their life is over.
+func (e *ParticleEmitter) Stop() {
+	e.emitting = false
+	e.accumulator = 0
+}
+
+// Submit method adds the emitter draw to the render queue.
+func (e *ParticleEmitter) Submit(queue *RenderQueue) {
+	_, y := GetWorldPos(e)
+	queue.Submit(e.renderLayer, 0, y, e.Draw)
+}
+
+// Update method spawns new particles, when the emitter is emitting, and moves
+// all alive particles for the given elapsed time in seconds.
+func (e *ParticleEmitter) Update(dt float64) {
+	if e.emitting && e.config.Rate > 0 {
+		e.accumulator += dt * e.config.Rate
+		for ; e.accumulator >= 1; e.accumulator-- {
+			e.spawn()
+		}
+	}
+	for i := range e.particles {
+		p := &e.particles[i]
+		if !p.alive {
+			continue
+		}
+		if p.age += dt; p.age >= p.life {
+			p.alive = false
+			e.free = append(e.free, i)
+			continue
+		}
+		p.vx += e.config.GravityX * dt
+		p.vy += e.config.GravityY * dt
+		p.x += p.vx * dt
+		p.y += p.vy * dt
+	}
+}
+
+var _ IDrawable = (*ParticleEmitter)(nil)
+var _ IRenderable = (*ParticleEmitter)(nil)
@@ -0,0 +1,204 @@
+package engine
+
+import "math"
+
+// PhysicsBody structure defines kinematic physics for an actor: velocity and
+// acceleration in pixels per second, gravity, friction, drag and maximum
+// speed, with simple platformer features. The actor moves with the engine
+// collision resolution, and the body reacts to the contacts found.
+//
+// Fields:
+//   - gravity: vertical acceleration always applied, in pixels per second
+//     squared. Positive gravity pulls down.
+//   - friction: horizontal deceleration, in pixels per second squared,
+//     applied while on ground and without horizontal acceleration.
+//   - drag: fraction of the velocity lost every second, applied always.
+//   - maxSpeedX, maxSpeedY: velocity limits for every axis, zero for no
+//     limit.
+//   - runAcceleration: horizontal acceleration used by Actor.Update for the
+//     arrow keys.
+//   - jumpSpeed: vertical speed set when the body jumps.
+//   - jumpBuffer: time, in seconds, a jump requested in the air is kept,
+//     so it is done when the body lands.
+type PhysicsBody struct {
+	velocityX, velocityY         float64
+	accelerationX, accelerationY float64
+	gravity                      float64
+	friction                     float64
+	drag                         float64
+	maxSpeedX, maxSpeedY         float64
+	runAcceleration              float64
+	jumpSpeed                    float64
+	jumpBuffer                   float64
+	jumpBufferLeft               float64
+	jumpRequested                bool
+	onGround                     bool
+}
+
+// NewPhysicsBody function creates a new PhysicsBody instance without gravity,
+// friction, drag or speed limits.
+func NewPhysicsBody() *PhysicsBody {
+	return &PhysicsBody{}
+}
+
+// -----------------------------------------------------------------------------
+// PhysicsBody private methods
+// -----------------------------------------------------------------------------
+
+// integrate method updates the body velocity for the given elapsed time in
+// seconds, and starts a requested jump when the body is on ground.
+func (b *PhysicsBody) integrate(dt float64) {
+	b.velocityX += b.accelerationX * dt
+	b.velocityY += (b.accelerationY + b.gravity) * dt
+	if b.onGround && b.accelerationX == 0 && b.friction > 0 {
+		decrease := math.Min(math.Abs(b.velocityX), b.friction*dt)
+		b.velocityX -= math.Copysign(decrease, b.velocityX)
+	}
+	if b.drag > 0 {
+		factor := math.Max(0, 1-b.drag*dt)
+		b.velocityX *= factor
+		b.velocityY *= factor
+	}
+	if b.maxSpeedX > 0 {
+		b.velocityX = math.Max(-b.maxSpeedX, math.Min(b.velocityX, b.maxSpeedX))
+	}
+	if b.maxSpeedY > 0 {
+		b.velocityY = math.Max(-b.maxSpeedY, math.Min(b.velocityY, b.maxSpeedY))
+	}
+	if b.jumpRequested {
+		if b.onGround {
+			b.velocityY = -b.jumpSpeed
+			b.onGround = false
+			b.jumpRequested = false
+		} else if b.jumpBufferLeft -= dt; b.jumpBufferLeft <= 0 {
+			b.jumpRequested = false
+		}
+	}
+}
+
+// resolve method updates the body with the contacts found when the actor
+// moved: it is on ground when it hits a surface below, and velocity against
+// any surface hit is removed.
+func (b *PhysicsBody) resolve(contacts []Contact) {
+	b.onGround = false
+	for _, contact := range contacts {
+		if contact.NormalY < 0 {
+			b.onGround = true
+		}
+		if b.velocityX*contact.NormalX < 0 {
+			b.velocityX = 0
+		}
+		if b.velocityY*contact.NormalY < 0 {
+			b.velocityY = 0
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+// PhysicsBody public methods
+// -----------------------------------------------------------------------------
+
+// AddImpulse method adds the given velocity change to the body.
+func (b *PhysicsBody) AddImpulse(dvx, dvy float64) *PhysicsBody {
+	b.velocityX += dvx
+	b.velocityY += dvy
+	return b
+}
+
+func (b *PhysicsBody) GetAcceleration() (float64, float64) {
+	return b.accelerationX, b.accelerationY
+}
+
+func (b *PhysicsBody) GetDrag() float64 {
+	return b.drag
+}
+
+func (b *PhysicsBody) GetFriction() float64 {
+	return b.friction
+}
+
+func (b *PhysicsBody) GetGravity() float64 {
+	return b.gravity
+}
+
+func (b *PhysicsBody) GetJump() (float64, float64) {
+	return b.jumpSpeed, b.jumpBuffer
+}
+
+func (b *PhysicsBody) GetMaxSpeed() (float64, float64) {
+	return b.maxSpeedX, b.maxSpeedY
+}
+
+func (b *PhysicsBody) GetRunAcceleration() float64 {
+	return b.runAcceleration
+}
+
+func (b *PhysicsBody) GetVelocity() (float64, float64) {
+	return b.velocityX, b.velocityY
+}
+
+// IsOnGround method returns if the body was standing on a surface after its
+// last move.
+func (b *PhysicsBody) IsOnGround() bool {
+	return b.onGround
+}
+
+// Jump method requests a jump. The body jumps in the next update if it is on
+// ground, or when it lands before the jump buffer time runs out.
+func (b *PhysicsBody) Jump() *PhysicsBody {
+	b.jumpRequested = true
+	b.jumpBufferLeft = b.jumpBuffer
+	return b
+}
+
+// SetAcceleration method sets the body acceleration in pixels per second
+// squared, which is kept until it is changed.
+func (b *PhysicsBody) SetAcceleration(ax, ay float64) *PhysicsBody {
+	b.accelerationX, b.accelerationY = ax, ay
+	return b
+}
+
+// SetDrag method sets the fraction of the velocity lost every second.
+func (b *PhysicsBody) SetDrag(drag float64) *PhysicsBody {
+	b.drag = drag
+	return b
+}
+
+// SetFriction method sets the horizontal deceleration on ground in pixels per
+// second squared.
+func (b *PhysicsBody) SetFriction(friction float64) *PhysicsBody {
+	b.friction = friction
+	return b
+}
+
+// SetGravity method sets the vertical gravity in pixels per second squared.
+func (b *PhysicsBody) SetGravity(gravity float64) *PhysicsBody {
+	b.gravity = gravity
+	return b
+}
+
+// SetJump method sets the jump vertical speed in pixels per second, and the
+// time in seconds a jump requested in the air is kept.
+func (b *PhysicsBody) SetJump(speed, buffer float64) *PhysicsBody {
+	b.jumpSpeed, b.jumpBuffer = speed, buffer
+	return b
+}
+
+// SetMaxSpeed method sets the velocity limit for every axis in pixels per
+// second, zero for no limit.
+func (b *PhysicsBody) SetMaxSpeed(maxSpeedX, maxSpeedY float64) *PhysicsBody {
+	b.maxSpeedX, b.maxSpeedY = maxSpeedX, maxSpeedY
+	return b
+}
+
+// SetRunAcceleration method sets the horizontal acceleration used for the
+// arrow keys in pixels per second squared.
+func (b *PhysicsBody) SetRunAcceleration(acceleration float64) *PhysicsBody {
+	b.runAcceleration = acceleration
+	return b
+}
+
+func (b *PhysicsBody) SetVelocity(vx, vy float64) *PhysicsBody {
+	b.velocityX, b.velocityY = vx, vy
+	return b
+}
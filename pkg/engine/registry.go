@@ -0,0 +1,201 @@
+package engine
+
+// Registry structure owns all objects in a game world and indexes them by ID,
+// so they can be looked up without scanning. Objects can also be queried by
+// name and tag.
+//
+// Objects added or removed while the registry is being iterated are not
+// applied until the iteration ends, so iteration always sees a stable list.
+// Hooks are called when objects are actually added to or removed from the
+// registry.
+type Registry struct {
+	objects   []IBase
+	byID      map[string]IBase
+	iterating int
+	pending   []registryOp
+	onAdded   []func(IBase)
+	onRemoved []func(IBase)
+}
+
+// registryOp structure is an add or remove requested while the registry was
+// being iterated. Adds carry the object, removes only the ID.
+type registryOp struct {
+	obj IBase
+	id  string
+}
+
+// NewRegistry function creates a new Registry instance.
+func NewRegistry() *Registry {
+	return &Registry{
+		byID: make(map[string]IBase),
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Registry private methods
+// -----------------------------------------------------------------------------
+
+func (r *Registry) add(obj IBase) {
+	if _, ok := r.byID[obj.GetID()]; ok {
+		return
+	}
+	r.objects = append(r.objects, obj)
+	r.byID[obj.GetID()] = obj
+	trackIDIndex(obj, 1)
+	for _, hook := range r.onAdded {
+		hook(obj)
+	}
+}
+
+// flush method applies all adds and removes requested while the registry was
+// being iterated, in the order they were requested.
+func (r *Registry) flush() {
+	pending := r.pending
+	r.pending = nil
+	for _, op := range pending {
+		if op.obj != nil {
+			r.add(op.obj)
+		} else {
+			r.remove(op.id)
+		}
+	}
+}
+
+func (r *Registry) remove(id string) {
+	obj, ok := r.byID[id]
+	if !ok {
+		return
+	}
+	delete(r.byID, id)
+	trackIDIndex(obj, -1)
+	for i, o := range r.objects {
+		if o.GetID() == id {
+			r.objects = append(r.objects[:i:i], r.objects[i+1:]...)
+			break
+		}
+	}
+	for _, hook := range r.onRemoved {
+		hook(obj)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Registry public methods
+// -----------------------------------------------------------------------------
+
+// Add method adds an object to the registry. Objects already in the registry
+// are ignored. If the registry is being iterated, the object is added when
+// the iteration ends.
+func (r *Registry) Add(obj IBase) {
+	if r.iterating > 0 {
+		r.pending = append(r.pending, registryOp{obj: obj})
+		return
+	}
+	r.add(obj)
+}
+
+// Each method calls the given function for every object in the registry, in
+// the order they were added. Iteration stops when the function returns
+// false.
+func (r *Registry) Each(visit func(IBase) bool) {
+	r.iterating++
+	for _, obj := range r.objects {
+		if !visit(obj) {
+			break
+		}
+	}
+	if r.iterating--; r.iterating == 0 {
+		r.flush()
+	}
+}
+
+// FindByName method returns all objects with the given name, in the order
+// they were added.
+func (r *Registry) FindByName(name string) []IBase {
+	var result []IBase
+	for _, obj := range r.objects {
+		if obj.GetName() == name {
+			result = append(result, obj)
+		}
+	}
+	return result
+}
+
+// FindByTag method returns all objects with the given tag, in the order they
+// were added.
+func (r *Registry) FindByTag(tag string) []IBase {
+	var result []IBase
+	for _, obj := range r.objects {
+		if obj.GetTag() == tag {
+			result = append(result, obj)
+		}
+	}
+	return result
+}
+
+// FindFirstByName method returns the first object added with the given name.
+func (r *Registry) FindFirstByName(name string) (IBase, bool) {
+	for _, obj := range r.objects {
+		if obj.GetName() == name {
+			return obj, true
+		}
+	}
+	return nil, false
+}
+
+// FindFirstByTag method returns the first object added with the given tag.
+func (r *Registry) FindFirstByTag(tag string) (IBase, bool) {
+	for _, obj := range r.objects {
+		if obj.GetTag() == tag {
+			return obj, true
+		}
+	}
+	return nil, false
+}
+
+// Get method returns the object with the given ID.
+func (r *Registry) Get(id string) (IBase, bool) {
+	obj, ok := r.byID[id]
+	return obj, ok
+}
+
+// GetAll method returns all objects in the registry, in the order they were
+// added. The returned slice must not be modified.
+func (r *Registry) GetAll() []IBase {
+	return r.objects
+}
+
+// Has method returns if an object with the given ID is in the registry.
+func (r *Registry) Has(id string) bool {
+	_, ok := r.byID[id]
+	return ok
+}
+
+func (r *Registry) Len() int {
+	return len(r.objects)
+}
+
+// OnAdded method adds a function called every time an object is added to
+// the registry.
+func (r *Registry) OnAdded(hook func(IBase)) *Registry {
+	r.onAdded = append(r.onAdded, hook)
+	return r
+}
+
+// OnRemoved method adds a function called every time an object is removed
+// from the registry.
+func (r *Registry) OnRemoved(hook func(IBase)) *Registry {
+	r.onRemoved = append(r.onRemoved, hook)
+	return r
+}
+
+// Remove method removes the object with the given ID from the registry. If
+// the registry is being iterated, the object is removed when the iteration
+// ends.
+func (r *Registry) Remove(id string) {
+	if r.iterating > 0 {
+		r.pending = append(r.pending, registryOp{id: id})
+		return
+	}
+	r.remove(id)
+}
@@ -0,0 +1,132 @@
+package engine
+
+import (
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Default render layers. Lower layers are drawn first. Any other integer
+// value can be used as a layer too.
+const (
+	RenderLayerBackground = 0
+	RenderLayerWorld      = 10
+	RenderLayerForeground = 20
+	RenderLayerUI         = 30
+)
+
+// RenderFunc type defines the function called to draw a render item.
+type RenderFunc func(*ebiten.Image, *Camera)
+
+// IRenderable interface defines objects that submit their draws to a render
+// queue instead of drawing directly.
+type IRenderable interface {
+	Submit(*RenderQueue)
+}
+
+// CheckRenderable function checks if the given object can be submitted to a
+// render queue.
+func CheckRenderable(obj any) (IRenderable, bool) {
+	result, ok := obj.(IRenderable)
+	return result, ok
+}
+
+// renderItem structure defines every draw submitted to the render queue.
+type renderItem struct {
+	layer int
+	z     float64
+	sortY float64
+	order int
+	draw  RenderFunc
+}
+
+// RenderQueue structure collects all draws for a frame and executes them
+// ordered by layer, z-index and, for layers with Y-sorting enabled, by the
+// vertical position of the item. Items with the same sorting keys are drawn
+// in submission order.
+type RenderQueue struct {
+	items []renderItem
+	ySort map[int]bool
+}
+
+// NewRenderQueue function creates a new empty RenderQueue instance.
+func NewRenderQueue() *RenderQueue {
+	return &RenderQueue{
+		ySort: make(map[int]bool),
+	}
+}
+
+// -----------------------------------------------------------------------------
+// RenderQueue private methods
+// -----------------------------------------------------------------------------
+
+// less method returns if the item i has to be drawn before the item j.
+func (q *RenderQueue) less(i, j int) bool {
+	a, b := &q.items[i], &q.items[j]
+	if a.layer != b.layer {
+		return a.layer < b.layer
+	}
+	if a.z != b.z {
+		return a.z < b.z
+	}
+	if q.ySort[a.layer] && a.sortY != b.sortY {
+		return a.sortY < b.sortY
+	}
+	return a.order < b.order
+}
+
+// -----------------------------------------------------------------------------
+// RenderQueue public methods
+// -----------------------------------------------------------------------------
+
+// Draw method sorts and draws all items submitted to the queue, and resets
+// the queue for the next frame.
+func (q *RenderQueue) Draw(screen *ebiten.Image, camera *Camera) {
+	sort.Slice(q.items, q.less)
+	for _, item := range q.items {
+		item.draw(screen, camera)
+	}
+	q.Reset()
+}
+
+// IsLayerYSorted method returns if items in the given layer are sorted by
+// their vertical position.
+func (q *RenderQueue) IsLayerYSorted(layer int) bool {
+	return q.ySort[layer]
+}
+
+func (q *RenderQueue) Len() int {
+	return len(q.items)
+}
+
+// Reset method removes all items from the queue, keeping allocated memory.
+func (q *RenderQueue) Reset() {
+	for i := range q.items {
+		q.items[i].draw = nil
+	}
+	q.items = q.items[:0]
+}
+
+// SetLayerYSort method enables or disables sorting by vertical position for
+// items in the given layer.
+func (q *RenderQueue) SetLayerYSort(layer int, ySort bool) *RenderQueue {
+	q.ySort[layer] = ySort
+	return q
+}
+
+// Submit method adds a new draw to the queue. SortY is the vertical world
+// position used for Y-sorting, usually the bottom of the sprite.
+func (q *RenderQueue) Submit(layer int, z float64, sortY float64, draw RenderFunc) {
+	q.items = append(q.items, renderItem{
+		layer: layer,
+		z:     z,
+		sortY: sortY,
+		order: len(q.items),
+		draw:  draw,
+	})
+}
+
+// SubmitDrawable method adds a drawable object to the queue.
+func (q *RenderQueue) SubmitDrawable(layer int, z float64, sortY float64, drawable IDrawable) {
+	q.Submit(layer, z, sortY, drawable.Draw)
+}
@@ -0,0 +1,240 @@
+package engine
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// IScene interface defines a game screen managed by the scene manager, like
+// the gameplay, a pause menu or a dialog.
+//
+// Hooks are called by the scene manager: OnEnter when the scene is added to
+// the stack, OnExit when it is removed, OnPause when other scene is pushed
+// on top of it and OnResume when that scene is popped.
+type IScene interface {
+	IBase
+	Draw(*ebiten.Image)
+	IsTransparent() bool
+	OnEnter(*SceneManager)
+	OnExit()
+	OnPause()
+	OnResume()
+	Update() error
+}
+
+// Scene structure defines the common data for all scenes, with hooks that do
+// nothing. Scenes embed it and override the hooks they require. Scenes
+// overriding OnEnter should call the embedded OnEnter to keep the manager.
+//
+// A transparent scene lets the scenes below it in the stack be drawn, which
+// is used for overlays like pause menus or dialogs. Only the top scene is
+// updated.
+type Scene struct {
+	*Base
+	manager     *SceneManager
+	transparent bool
+}
+
+// NewScene function creates a new Scene instance.
+func NewScene(name string) *Scene {
+	return &Scene{
+		Base: NewBase(name),
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Scene public methods
+// -----------------------------------------------------------------------------
+
+// GetManager method returns the scene manager the scene has entered.
+func (s *Scene) GetManager() *SceneManager {
+	return s.manager
+}
+
+// IsTransparent method returns if scenes below this one are drawn.
+func (s *Scene) IsTransparent() bool {
+	return s.transparent
+}
+
+func (s *Scene) OnEnter(manager *SceneManager) {
+	s.manager = manager
+}
+
+func (s *Scene) OnExit() {
+}
+
+func (s *Scene) OnPause() {
+}
+
+func (s *Scene) OnResume() {
+}
+
+// SetTransparent method sets if scenes below this one are drawn.
+func (s *Scene) SetTransparent(transparent bool) *Scene {
+	s.transparent = transparent
+	return s
+}
+
+// SceneManager structure defines the engine game loop, implementing the
+// ebiten.Game interface. It keeps a stack of scenes: only the top scene is
+// updated, and it is drawn over any transparent scene below it.
+//
+// Scenes can be pushed, popped or switched immediately or with a transition.
+// During a transition scenes are not updated, and the transition draws the
+// previous and the new stack of scenes.
+type SceneManager struct {
+	width, height int
+	stack         []IScene
+	transition    ITransition
+	fromStack     []IScene
+	fromImage     *ebiten.Image
+	toImage       *ebiten.Image
+}
+
+// NewSceneManager function creates a new SceneManager instance with the given
+// logical screen size.
+func NewSceneManager(width, height int) *SceneManager {
+	return &SceneManager{
+		width:  width,
+		height: height,
+	}
+}
+
+// -----------------------------------------------------------------------------
+// SceneManager private methods
+// -----------------------------------------------------------------------------
+
+// drawStack method draws the given stack of scenes, starting at the lowest
+// scene visible through the transparent scenes on top of it.
+func (m *SceneManager) drawStack(screen *ebiten.Image, stack []IScene) {
+	start := len(stack) - 1
+	for start > 0 && stack[start].IsTransparent() {
+		start--
+	}
+	for i := max(start, 0); i < len(stack); i++ {
+		stack[i].Draw(screen)
+	}
+}
+
+// getImage method returns the given offscreen image, creating it again when
+// it does not match the screen size.
+func (m *SceneManager) getImage(img *ebiten.Image, screen *ebiten.Image) *ebiten.Image {
+	if img == nil || img.Bounds().Size() != screen.Bounds().Size() {
+		if img != nil {
+			img.Deallocate()
+		}
+		return ebiten.NewImage(screen.Bounds().Dx(), screen.Bounds().Dy())
+	}
+	img.Clear()
+	return img
+}
+
+// startTransition method keeps the current stack to be drawn as the previous
+// state of the given transition.
+func (m *SceneManager) startTransition(transition ITransition) {
+	if transition == nil {
+		return
+	}
+	transition.Reset()
+	m.transition = transition
+	m.fromStack = append([]IScene(nil), m.stack...)
+}
+
+// -----------------------------------------------------------------------------
+// SceneManager public methods
+// -----------------------------------------------------------------------------
+
+func (m *SceneManager) Draw(screen *ebiten.Image) {
+	if m.transition == nil {
+		m.drawStack(screen, m.stack)
+		return
+	}
+	m.fromImage = m.getImage(m.fromImage, screen)
+	m.toImage = m.getImage(m.toImage, screen)
+	m.drawStack(m.fromImage, m.fromStack)
+	m.drawStack(m.toImage, m.stack)
+	m.transition.Draw(screen, m.fromImage, m.toImage)
+}
+
+// GetCurrent method returns the scene on top of the stack.
+func (m *SceneManager) GetCurrent() IScene {
+	if len(m.stack) == 0 {
+		return nil
+	}
+	return m.stack[len(m.stack)-1]
+}
+
+// GetScenes method returns all scenes in the stack, from bottom to top.
+func (m *SceneManager) GetScenes() []IScene {
+	return m.stack
+}
+
+// IsTransitioning method returns if a transition is being played.
+func (m *SceneManager) IsTransitioning() bool {
+	return m.transition != nil
+}
+
+func (m *SceneManager) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return m.width, m.height
+}
+
+func (m *SceneManager) Len() int {
+	return len(m.stack)
+}
+
+// Pop method removes the scene on top of the stack, and resumes the scene
+// below it, with the given transition, which can be nil.
+func (m *SceneManager) Pop(transition ITransition) IScene {
+	current := m.GetCurrent()
+	if current == nil {
+		return nil
+	}
+	m.startTransition(transition)
+	m.stack = m.stack[:len(m.stack)-1]
+	current.OnExit()
+	if next := m.GetCurrent(); next != nil {
+		next.OnResume()
+	}
+	return current
+}
+
+// Push method adds the scene on top of the stack, and pauses the current
+// scene, with the given transition, which can be nil.
+func (m *SceneManager) Push(scene IScene, transition ITransition) {
+	m.startTransition(transition)
+	if current := m.GetCurrent(); current != nil {
+		current.OnPause()
+	}
+	m.stack = append(m.stack, scene)
+	scene.OnEnter(m)
+}
+
+// Switch method replaces the scene on top of the stack with the given scene,
+// with the given transition, which can be nil.
+func (m *SceneManager) Switch(scene IScene, transition ITransition) {
+	m.startTransition(transition)
+	if current := m.GetCurrent(); current != nil {
+		m.stack = m.stack[:len(m.stack)-1]
+		current.OnExit()
+	}
+	m.stack = append(m.stack, scene)
+	scene.OnEnter(m)
+}
+
+// Update method updates the scene on top of the stack, or the transition
+// being played.
+func (m *SceneManager) Update() error {
+	if m.transition != nil {
+		m.transition.Update(1 / float64(ebiten.TPS()))
+		if m.transition.IsFinished() {
+			m.transition = nil
+			m.fromStack = nil
+		}
+		return nil
+	}
+	if current := m.GetCurrent(); current != nil {
+		return current.Update()
+	}
+	return nil
+}
+
+var _ ebiten.Game = (*SceneManager)(nil)
@@ -0,0 +1,165 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ISceneNode interface defines entities that can be attached to other
+// entities, building a scene graph. Every node has a local transform relative
+// to its parent, and the world transform is the composition of all local
+// transforms from the root to the node.
+//
+// Nodes are attached and detached with AddChild, RemoveChild and Reparent
+// functions, which keep parent and children links consistent.
+type ISceneNode interface {
+	IEntity
+	GetChildren() []ISceneNode
+	GetLocalTransform() ebiten.GeoM
+	GetParent() ISceneNode
+	GetPrevWorldPos() (float64, float64)
+	setChildren([]ISceneNode)
+	setParent(ISceneNode)
+}
+
+// AddChild function attaches the child node to the parent node. If the child
+// was attached to other node, it is detached first. Child local position is
+// kept, so it is now relative to the new parent, and it is not interpolated
+// from its old world position. It returns an error if the child is the
+// parent or one of its ancestors, which would create a cycle.
+func AddChild(parent ISceneNode, child ISceneNode) error {
+	if IsAncestor(child, parent) {
+		return fmt.Errorf("can not add %s as child of %s, it would create a cycle", child.GetName(), parent.GetName())
+	}
+	if oldParent := child.GetParent(); oldParent != nil {
+		RemoveChild(oldParent, child)
+	}
+	parent.setChildren(append(parent.GetChildren(), child))
+	child.setParent(parent)
+	child.StorePrevPos()
+	return nil
+}
+
+// CollectColliders function returns all colliders in the scene graph starting
+// at the given root node, in traversal order.
+func CollectColliders(root ISceneNode) []ICollider {
+	var result []ICollider
+	WalkSceneGraph(root, func(node ISceneNode) bool {
+		if collider, ok := CheckCollidable(node); ok {
+			result = append(result, collider)
+		}
+		return true
+	})
+	return result
+}
+
+// GetInterpolationOffset function returns the translation, in world
+// coordinates, from the node current world position to its position
+// interpolated with the previous world position for the given alpha.
+func GetInterpolationOffset(node ISceneNode, alpha float64) (float64, float64) {
+	x, y := GetWorldPos(node)
+	prevX, prevY := node.GetPrevWorldPos()
+	return (prevX - x) * (1 - alpha), (prevY - y) * (1 - alpha)
+}
+
+// GetWorldPos function returns the position of the node in world
+// coordinates.
+func GetWorldPos(node ISceneNode) (float64, float64) {
+	geoM := GetWorldTransform(node)
+	return geoM.Apply(0, 0)
+}
+
+// GetWorldTransform function returns the transform from the node local
+// coordinates to world coordinates.
+func GetWorldTransform(node ISceneNode) ebiten.GeoM {
+	geoM := node.GetLocalTransform()
+	for parent := node.GetParent(); parent != nil; parent = parent.GetParent() {
+		geoM.Concat(parent.GetLocalTransform())
+	}
+	return geoM
+}
+
+// IsAncestor function returns if the ancestor node is the given node or any
+// of its parents.
+func IsAncestor(ancestor ISceneNode, node ISceneNode) bool {
+	for ; node != nil; node = node.GetParent() {
+		if node.GetID() == ancestor.GetID() {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveChild function detaches the child node from the parent node. The
+// child local position is now a world position, and it is not interpolated
+// from its old world position.
+func RemoveChild(parent ISceneNode, child ISceneNode) {
+	children := parent.GetChildren()
+	for i, node := range children {
+		if node.GetID() == child.GetID() {
+			parent.setChildren(append(children[:i:i], children[i+1:]...))
+			child.setParent(nil)
+			child.StorePrevPos()
+			return
+		}
+	}
+}
+
+// Reparent function attaches the child node to a new parent node, or detaches
+// it when the new parent is nil. If keepWorldPos is true, the child local
+// position is updated so it does not move in the world. It returns an error,
+// without changing the scene graph, if the child is the new parent or one of
+// its ancestors.
+func Reparent(child ISceneNode, newParent ISceneNode, keepWorldPos bool) error {
+	if newParent != nil && IsAncestor(child, newParent) {
+		return fmt.Errorf("can not reparent %s to %s, it would create a cycle", child.GetName(), newParent.GetName())
+	}
+	x, y := GetWorldPos(child)
+	if oldParent := child.GetParent(); oldParent != nil {
+		RemoveChild(oldParent, child)
+	}
+	if newParent != nil {
+		AddChild(newParent, child)
+	}
+	if !keepWorldPos {
+		return nil
+	}
+	if newParent != nil {
+		parentGeoM := GetWorldTransform(newParent)
+		if parentGeoM.IsInvertible() {
+			parentGeoM.Invert()
+			x, y = parentGeoM.Apply(x, y)
+		}
+	}
+	child.SetPos(x, y)
+	child.StorePrevPos()
+	return nil
+}
+
+// SubmitSceneGraph function adds all renderable and drawable nodes in the
+// scene graph starting at the given root node to the render queue. Drawable
+// nodes that are not renderable are submitted to the given layer.
+func SubmitSceneGraph(root ISceneNode, queue *RenderQueue, layer int) {
+	WalkSceneGraph(root, func(node ISceneNode) bool {
+		if result, ok := CheckRenderable(node); ok {
+			result.Submit(queue)
+		} else if result, ok := CheckDrawable(node); ok {
+			_, y := GetWorldPos(node)
+			queue.SubmitDrawable(layer, 0, y, result)
+		}
+		return true
+	})
+}
+
+// WalkSceneGraph function visits all nodes in the scene graph starting at the
+// given root node, parents before children. Children of a node are not
+// visited when the function returns false for it.
+func WalkSceneGraph(root ISceneNode, visit func(ISceneNode) bool) {
+	if !visit(root) {
+		return
+	}
+	for _, child := range root.GetChildren() {
+		WalkSceneGraph(child, visit)
+	}
+}
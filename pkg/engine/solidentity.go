@@ -1,37 +1,116 @@
 package engine
 
-import "image"
+import "github.com/jrecuero/ebiplay/pkg/tools"
 
 type ISolidEntity interface {
 	IEntity
 	ICollider
 }
 
+// SolidEntity structure defines an entity that takes part in collisions. It
+// is solid and in the default layer, colliding with all layers, unless it is
+// set as a trigger or other layers are set.
+//
+// The entity collides with a box with its position and size, unless a
+// hitbox is set, a shape with coordinates relative to the entity position.
 type SolidEntity struct {
 	*Entity
+	collisionLayer CollisionLayer
+	collisionMask  CollisionLayer
+	hitbox         tools.IShape
+	oneWay         bool
+	trigger        bool
 }
 
 func NewSolidEntity(name string, x, y float64, w, h int) *SolidEntity {
 	return &SolidEntity{
-		Entity: NewEntity(name, x, y, w, h),
+		Entity:         NewEntity(name, x, y, w, h),
+		collisionLayer: CollisionLayerDefault,
+		collisionMask:  CollisionMaskAll,
 	}
 }
 
-func (e *SolidEntity) CollideWith(IEntity) {
+// GetBounds method returns the axis aligned rectangle that contains the
+// entity shape.
+func (e *SolidEntity) GetBounds() tools.Rect {
+	return e.GetShape().GetBounds()
 }
 
-func (e *SolidEntity) GetBounds() image.Rectangle {
-	maxX := int(e.x + float64(e.width))
-	maxY := int(e.y + float64(e.height))
-	return image.Rectangle{
-		Min: image.Pt(int(e.x), int(e.y)),
-		Max: image.Pt(maxX, maxY),
+func (e *SolidEntity) GetCollisionLayer() CollisionLayer {
+	return e.collisionLayer
+}
+
+func (e *SolidEntity) GetCollisionMask() CollisionLayer {
+	return e.collisionMask
+}
+
+// GetHitbox method returns the entity hitbox, relative to the entity
+// position, or nil when the entity collides with its box.
+func (e *SolidEntity) GetHitbox() tools.IShape {
+	return e.hitbox
+}
+
+// GetShape method returns the entity shape in world coordinates, moved by the
+// entity world transform.
+func (e *SolidEntity) GetShape() tools.IShape {
+	if e.hitbox != nil {
+		return TransformShape(e.hitbox, GetWorldTransform(e))
 	}
+	x, y := GetWorldPos(e)
+	return tools.NewBox(x, y, float64(e.width), float64(e.height))
+}
+
+// IsOneWay method returns if the entity only blocks colliders moving down
+// onto its top side.
+func (e *SolidEntity) IsOneWay() bool {
+	return e.oneWay
 }
 
+// IsSolid method returns if the entity blocks movement, which is true for
+// all entities but triggers.
 func (e *SolidEntity) IsSolid() bool {
-	return true
+	return !e.trigger
+}
+
+// IsTrigger method returns if the entity only reports overlaps without
+// blocking movement.
+func (e *SolidEntity) IsTrigger() bool {
+	return e.trigger
+}
+
+// SetCollisionLayer method sets the layers the entity belongs to.
+func (e *SolidEntity) SetCollisionLayer(layer CollisionLayer) *SolidEntity {
+	e.collisionLayer = layer
+	return e
+}
+
+// SetCollisionMask method sets the layers the entity collides with.
+func (e *SolidEntity) SetCollisionMask(mask CollisionLayer) *SolidEntity {
+	e.collisionMask = mask
+	return e
+}
+
+// SetHitbox method sets the entity hitbox, a shape with coordinates relative
+// to the entity position. A nil hitbox collides with the entity box.
+func (e *SolidEntity) SetHitbox(hitbox tools.IShape) *SolidEntity {
+	e.hitbox = hitbox
+	return e
+}
+
+// SetOneWay method sets if the entity only blocks colliders moving down onto
+// its top side, like a platform that can be jumped through from below.
+func (e *SolidEntity) SetOneWay(oneWay bool) *SolidEntity {
+	e.oneWay = oneWay
+	return e
+}
+
+// SetTrigger method sets if the entity only reports overlaps without
+// blocking movement.
+func (e *SolidEntity) SetTrigger(trigger bool) *SolidEntity {
+	e.trigger = trigger
+	return e
 }
 
 var _ IEntity = (*SolidEntity)(nil)
 var _ ICollider = (*SolidEntity)(nil)
+var _ IOneWay = (*SolidEntity)(nil)
@@ -7,18 +7,32 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
+// frameMirror structure defines a frame type that reuses the frames from
+// other frame type, mirrored when it is drawn.
+type frameMirror struct {
+	source string
+	flip   Flip
+}
+
+// SpriteSheet structure defines an image with all animation frames for a
+// sprite. Frames are grouped by frame type, and the current frame type is
+// animated by calling Update with the elapsed time, showing every frame for
+// the frame duration, in seconds.
 type SpriteSheet struct {
-	Image        *ebiten.Image
-	Rows         int
-	Columns      int
-	Width        int
-	Height       int
-	frameTypes   []string
-	frameType    string
-	frameMap     map[string][]int
-	frameIndex   int
-	frameSpeed   int
-	frameCounter int
+	Image         *ebiten.Image
+	Rows          int
+	Columns       int
+	Width         int
+	Height        int
+	frameTypes    []string
+	frameType     string
+	frameMap      map[string][]int
+	frameMirrors  map[string]frameMirror
+	frameIndex    int
+	frameDuration float64
+	frameElapsed  float64
+	frameLoops    int
+	paused        bool
 }
 
 func NewSpriteSheet(image *ebiten.Image, rows, columns, width, height int) *SpriteSheet {
@@ -35,6 +49,8 @@ func NewSpriteSheetImage(image *ebiten.Image, width, height int) *SpriteSheet {
 	return NewSpriteSheet(image, 1, 1, width, height)
 }
 
+// GetFrameFor method returns the image for the current frame of the given
+// frame type. Frames are advanced by Update, not by this method.
 func (s *SpriteSheet) GetFrameFor(frameType string) *ebiten.Image {
 	if !s.IsValidFrameType(frameType) {
 		log.Fatalf("invalid frame type %s", frameType)
@@ -42,15 +58,48 @@ func (s *SpriteSheet) GetFrameFor(frameType string) *ebiten.Image {
 	// Update the frame type if it is a different one and reset all counters
 	// and indexes.
 	s.UpdateFrameType(frameType)
-	framemap := s.frameMap[frameType]
-	if s.frameCounter = (s.frameCounter + 1) % s.frameSpeed; s.frameCounter == 0 {
-		s.frameIndex = (s.frameIndex + 1) % 4
-	}
-	i := s.frameIndex * 2
+	return s.GetFrameAt(frameType, s.frameIndex)
+}
+
+// GetFrameAt method returns the image for the given frame index in the given
+// frame type, without updating any animation counter.
+func (s *SpriteSheet) GetFrameAt(frameType string, index int) *ebiten.Image {
+	framemap := s.getFrameMapFor(frameType)
+	i := (index % (len(framemap) / 2)) * 2
 	xIndex, yIndex := framemap[i], framemap[i+1]
 	return s.Image.SubImage(image.Rect(xIndex, yIndex, xIndex+s.Width, yIndex+s.Height)).(*ebiten.Image)
 }
 
+// GetFrameCount method returns the number of frames for the given frame type.
+func (s *SpriteSheet) GetFrameCount(frameType string) int {
+	return len(s.getFrameMapFor(frameType)) / 2
+}
+
+func (s *SpriteSheet) GetFrameIndex() int {
+	return s.frameIndex
+}
+
+// GetFrameProgress method returns the number of cycles, as a fraction, the
+// current frame type animation has played since it was set.
+func (s *SpriteSheet) GetFrameProgress() float64 {
+	count := s.GetFrameCount(s.frameType)
+	if count == 0 || s.frameDuration <= 0 {
+		return 0
+	}
+	elapsed := float64(s.frameIndex)*s.frameDuration + s.frameElapsed
+	return float64(s.frameLoops) + elapsed/(float64(count)*s.frameDuration)
+}
+
+// GetFlipFor method returns how frames for the given frame type have to be
+// mirrored when they are drawn. Frame types not declared as a mirror return
+// FlipNone.
+func (s *SpriteSheet) GetFlipFor(frameType string) Flip {
+	if mirror, ok := s.frameMirrors[frameType]; ok {
+		return mirror.flip
+	}
+	return FlipNone
+}
+
 func (s *SpriteSheet) GetFrameType() string {
 	return s.frameType
 }
@@ -61,6 +110,20 @@ func (s *SpriteSheet) GetSpriteFromRowAndCol(row, col int) *ebiten.Image {
 	return s.Image.SubImage(image.Rect(x, y, x+s.Width, y+s.Height)).(*ebiten.Image)
 }
 
+// getFrameMapFor method returns frame positions for the given frame type,
+// resolving mirrored frame types to their source frame type.
+func (s *SpriteSheet) getFrameMapFor(frameType string) []int {
+	if mirror, ok := s.frameMirrors[frameType]; ok {
+		return s.frameMap[mirror.source]
+	}
+	return s.frameMap[frameType]
+}
+
+// IsPaused method returns if the frame animation is paused.
+func (s *SpriteSheet) IsPaused() bool {
+	return s.paused
+}
+
 func (s *SpriteSheet) IsValidFrameType(frameType string) bool {
 	for _, ft := range s.frameTypes {
 		if ft == frameType {
@@ -75,13 +138,38 @@ func (s *SpriteSheet) SetFrameMap(m map[string][]int) *SpriteSheet {
 	for key := range m {
 		s.frameTypes = append(s.frameTypes, key)
 	}
+	for key := range s.frameMirrors {
+		s.frameTypes = append(s.frameTypes, key)
+	}
 	s.frameMap = m
 	s.frameType = s.frameTypes[0] // by default set to the first entry.
 	return s
 }
 
-func (s *SpriteSheet) SetFrameSpeed(speed int) *SpriteSheet {
-	s.frameSpeed = speed
+// SetFrameMirror method declares the given frame type as a mirror of the
+// source frame type, so both share the same frames in the sprite sheet image
+// and frames are flipped when they are drawn. Source frame type has to be
+// already defined in the frame map.
+func (s *SpriteSheet) SetFrameMirror(frameType string, source string, flip Flip) *SpriteSheet {
+	if _, ok := s.frameMap[source]; !ok {
+		log.Fatalf("invalid mirror source frame type %s", source)
+	}
+	if s.frameMirrors == nil {
+		s.frameMirrors = make(map[string]frameMirror)
+	}
+	if !s.IsValidFrameType(frameType) {
+		s.frameTypes = append(s.frameTypes, frameType)
+	}
+	s.frameMirrors[frameType] = frameMirror{
+		source: source,
+		flip:   flip,
+	}
+	return s
+}
+
+// SetFrameDuration method sets the time, in seconds, every frame is shown.
+func (s *SpriteSheet) SetFrameDuration(duration float64) *SpriteSheet {
+	s.frameDuration = duration
 	return s
 }
 
@@ -90,10 +178,35 @@ func (s *SpriteSheet) SetFrameType(t string) *SpriteSheet {
 	return s
 }
 
+// SetPaused method pauses or resumes the frame animation. A paused sprite
+// sheet keeps returning the current frame.
+func (s *SpriteSheet) SetPaused(paused bool) *SpriteSheet {
+	s.paused = paused
+	return s
+}
+
+// Update method advances the current frame type animation by the given
+// elapsed time in seconds. Paused sprite sheets do not advance.
+func (s *SpriteSheet) Update(dt float64) {
+	if s.paused || s.frameDuration <= 0 {
+		return
+	}
+	count := s.GetFrameCount(s.frameType)
+	if count == 0 {
+		return
+	}
+	for s.frameElapsed += dt; s.frameElapsed >= s.frameDuration; s.frameElapsed -= s.frameDuration {
+		if s.frameIndex = (s.frameIndex + 1) % count; s.frameIndex == 0 {
+			s.frameLoops++
+		}
+	}
+}
+
 func (s *SpriteSheet) UpdateFrameType(frameType string) {
 	if frameType != s.frameType {
 		s.frameType = frameType
 		s.frameIndex = 0
-		s.frameCounter = 0
+		s.frameElapsed = 0
+		s.frameLoops = 0
 	}
 }
@@ -0,0 +1,188 @@
+package engine
+
+import (
+	"image"
+	"math"
+
+	"github.com/jrecuero/ebiplay/pkg/tools"
+)
+
+// TileCollisionGrid structure defines solid tiles in a tilemap, checked in
+// collision world queries without adding a collider for every tile. All
+// solid tiles are in the same collision layer.
+type TileCollisionGrid struct {
+	width, height         int
+	tileWidth, tileHeight int
+	solid                 []bool
+	layer                 CollisionLayer
+}
+
+// NewTileCollisionGrid function creates a new TileCollisionGrid instance with
+// the given size in tiles and tile size in pixels, and no solid tiles.
+func NewTileCollisionGrid(width, height, tileWidth, tileHeight int) *TileCollisionGrid {
+	return &TileCollisionGrid{
+		width:      width,
+		height:     height,
+		tileWidth:  tileWidth,
+		tileHeight: tileHeight,
+		solid:      make([]bool, width*height),
+		layer:      CollisionLayerDefault,
+	}
+}
+
+// NewTileCollisionGridFromLayer function creates a new TileCollisionGrid
+// instance for the tilemap, where every tile in the given layer is solid.
+func NewTileCollisionGridFromLayer(tilemap *TilemapJSON, index int) *TileCollisionGrid {
+	width, height := tilemap.GetTilemapSize()
+	tileWidth, tileHeight := tilemap.GetTileSize()
+	grid := NewTileCollisionGrid(width, height, tileWidth, tileHeight)
+	if index >= 0 && index < len(tilemap.Layers) {
+		for i, id := range tilemap.Layers[index].Data {
+			if i < len(grid.solid) {
+				grid.solid[i] = GetSpriteID(id) != 0
+			}
+		}
+	}
+	return grid
+}
+
+// -----------------------------------------------------------------------------
+// TileCollisionGrid private methods
+// -----------------------------------------------------------------------------
+
+// getTileRange method returns the first and last tiles the given rectangle
+// overlaps, clamped to the grid.
+func (g *TileCollisionGrid) getTileRange(rect tools.Rect) (image.Point, image.Point) {
+	minTile := image.Pt(
+		max(int(math.Floor(rect.X/float64(g.tileWidth))), 0),
+		max(int(math.Floor(rect.Y/float64(g.tileHeight))), 0),
+	)
+	maxTile := image.Pt(
+		min(int(math.Floor((rect.Right()-tools.Epsilon)/float64(g.tileWidth))), g.width-1),
+		min(int(math.Floor((rect.Bottom()-tools.Epsilon)/float64(g.tileHeight))), g.height-1),
+	)
+	return minTile, maxTile
+}
+
+// -----------------------------------------------------------------------------
+// TileCollisionGrid public methods
+// -----------------------------------------------------------------------------
+
+func (g *TileCollisionGrid) GetCollisionLayer() CollisionLayer {
+	return g.layer
+}
+
+// GetSolidTiles method returns the coordinates for all solid tiles
+// overlapping the given rectangle.
+func (g *TileCollisionGrid) GetSolidTiles(rect tools.Rect) []image.Point {
+	var result []image.Point
+	minTile, maxTile := g.getTileRange(rect)
+	for y := minTile.Y; y <= maxTile.Y; y++ {
+		for x := minTile.X; x <= maxTile.X; x++ {
+			if g.IsSolid(x, y) && rect.Overlaps(g.GetTileBounds(x, y)) {
+				result = append(result, image.Pt(x, y))
+			}
+		}
+	}
+	return result
+}
+
+// GetSize method returns the grid size in tiles.
+func (g *TileCollisionGrid) GetSize() (int, int) {
+	return g.width, g.height
+}
+
+// GetTileBounds method returns the rectangle for the tile at the given
+// coordinates.
+func (g *TileCollisionGrid) GetTileBounds(x, y int) tools.Rect {
+	return tools.NewRect(float64(x*g.tileWidth), float64(y*g.tileHeight), float64(g.tileWidth), float64(g.tileHeight))
+}
+
+// GetTileSize method returns the tile size in pixels.
+func (g *TileCollisionGrid) GetTileSize() (int, int) {
+	return g.tileWidth, g.tileHeight
+}
+
+// IsSolid method returns if the tile at the given coordinates is solid.
+// Tiles outside the grid are not solid.
+func (g *TileCollisionGrid) IsSolid(x, y int) bool {
+	if x < 0 || y < 0 || x >= g.width || y >= g.height {
+		return false
+	}
+	return g.solid[y*g.width+x]
+}
+
+// IsSolidAt method returns if the tile at the given position in pixels is
+// solid.
+func (g *TileCollisionGrid) IsSolidAt(x, y float64) bool {
+	return g.IsSolid(int(math.Floor(x/float64(g.tileWidth))), int(math.Floor(y/float64(g.tileHeight))))
+}
+
+// Raycast method walks the tiles crossed by the ray from origin, along the
+// unit direction, up to the given distance, and returns the first solid tile
+// found, the distance to it and the normal on the tile side hit. Rays starting
+// inside a solid tile hit at distance zero, with no normal.
+func (g *TileCollisionGrid) Raycast(origin, dir tools.Vec, maxDistance float64) (image.Point, float64, tools.Vec, bool) {
+	tileWidth, tileHeight := float64(g.tileWidth), float64(g.tileHeight)
+	tile := image.Pt(int(math.Floor(origin.X/tileWidth)), int(math.Floor(origin.Y/tileHeight)))
+	if g.IsSolid(tile.X, tile.Y) {
+		return tile, 0, tools.Vec{}, true
+	}
+	// distance along the ray to the next tile side, and between tile sides,
+	// for every axis.
+	stepX, nextX, deltaX := 0, math.Inf(1), math.Inf(1)
+	if dir.X > 0 {
+		stepX, deltaX = 1, tileWidth/dir.X
+		nextX = (float64(tile.X+1)*tileWidth - origin.X) / dir.X
+	} else if dir.X < 0 {
+		stepX, deltaX = -1, -tileWidth/dir.X
+		nextX = (float64(tile.X)*tileWidth - origin.X) / dir.X
+	}
+	stepY, nextY, deltaY := 0, math.Inf(1), math.Inf(1)
+	if dir.Y > 0 {
+		stepY, deltaY = 1, tileHeight/dir.Y
+		nextY = (float64(tile.Y+1)*tileHeight - origin.Y) / dir.Y
+	} else if dir.Y < 0 {
+		stepY, deltaY = -1, -tileHeight/dir.Y
+		nextY = (float64(tile.Y)*tileHeight - origin.Y) / dir.Y
+	}
+	for {
+		var distance float64
+		var normal tools.Vec
+		if nextX < nextY {
+			tile.X += stepX
+			distance, normal = nextX, tools.Vec{X: float64(-stepX)}
+			nextX += deltaX
+		} else {
+			tile.Y += stepY
+			distance, normal = nextY, tools.Vec{Y: float64(-stepY)}
+			nextY += deltaY
+		}
+		if distance > maxDistance || math.IsInf(distance, 1) {
+			return image.Point{}, 0, tools.Vec{}, false
+		}
+		if g.IsSolid(tile.X, tile.Y) {
+			return tile, distance, normal, true
+		}
+		// tiles outside the grid are never solid, so the ray can stop once it
+		// leaves the grid moving away from it.
+		if (tile.X < 0 && stepX <= 0) || (tile.X >= g.width && stepX >= 0) ||
+			(tile.Y < 0 && stepY <= 0) || (tile.Y >= g.height && stepY >= 0) {
+			return image.Point{}, 0, tools.Vec{}, false
+		}
+	}
+}
+
+// SetCollisionLayer method sets the layer all solid tiles belong to.
+func (g *TileCollisionGrid) SetCollisionLayer(layer CollisionLayer) *TileCollisionGrid {
+	g.layer = layer
+	return g
+}
+
+// SetSolid method sets if the tile at the given coordinates is solid.
+func (g *TileCollisionGrid) SetSolid(x, y int, solid bool) *TileCollisionGrid {
+	if x >= 0 && y >= 0 && x < g.width && y < g.height {
+		g.solid[y*g.width+x] = solid
+	}
+	return g
+}
@@ -2,6 +2,7 @@ package engine
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -41,6 +42,31 @@ func GetKeyStringToInt(key string) (int, int) {
 	return x, y
 }
 
+// getSortedKeys method returns all tile keys sorted by row and column, so
+// tiles are always processed in the same order.
+func (t *TileGrid) getSortedKeys() []string {
+	keys := make([]string, 0, len(t.tiles))
+	for key := range t.tiles {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		xi, yi := GetKeyStringToInt(keys[i])
+		xj, yj := GetKeyStringToInt(keys[j])
+		if yi != yj {
+			return yi < yj
+		}
+		return xi < xj
+	})
+	return keys
+}
+
+// isSameTile function checks if both tiles are the same entity. Entities are
+// compared by ID too, because an entity can be given embedded in a different
+// structure than the one added to the grid.
+func isSameTile(a, b IEntity) bool {
+	return a == b || a.GetID() == b.GetID()
+}
+
 func (t *TileGrid) AddTile(key string, tile IEntity) error {
 	t.tiles[key] = append(t.tiles[key], tile)
 	return nil
@@ -52,8 +78,8 @@ func (t *TileGrid) AddTileAt(x, y int, tile IEntity) error {
 }
 
 func (t *TileGrid) Draw(screen *ebiten.Image, camera *Camera) {
-	for _, tiles := range t.tiles {
-		for _, tile := range tiles {
+	for _, key := range t.getSortedKeys() {
+		for _, tile := range t.tiles[key] {
 			if result, ok := CheckDrawable(tile); ok {
 				result.Draw(screen, camera)
 			}
@@ -79,6 +105,14 @@ func (t *TileGrid) GetTilesAt(key string) TileSequence {
 	return t.tiles[key]
 }
 
+// GetTilePosFromCursor method returns the tile position under the given
+// screen position, like the cursor position, for any camera zoom and
+// rotation.
+func (t *TileGrid) GetTilePosFromCursor(camera *Camera, screenX, screenY int) (int, int) {
+	x, y := camera.ScreenToWorld(float64(screenX), float64(screenY))
+	return t.GetTilePosFromScreenPos(x, y)
+}
+
 func (t *TileGrid) GetTilePosFromScreenPos(x, y float64) (int, int) {
 	var tileX int = int(x) / t.width
 	var tileY int = int(y) / t.height
@@ -88,7 +122,7 @@ func (t *TileGrid) GetTilePosFromScreenPos(x, y float64) (int, int) {
 func (t *TileGrid) IsTileAt(key string, tile IEntity) bool {
 	if tiles := t.GetTilesAt(key); tiles != nil {
 		for _, atile := range tiles {
-			if atile == tile {
+			if isSameTile(atile, tile) {
 				return true
 			}
 		}
@@ -97,17 +131,32 @@ func (t *TileGrid) IsTileAt(key string, tile IEntity) bool {
 }
 
 func (t *TileGrid) MoveTileFromTo(from, to string, tile IEntity) error {
+	// move the tile stored in the grid, which could be embedding the given
+	// one.
+	for _, atile := range t.GetTilesAt(from) {
+		if isSameTile(atile, tile) {
+			tile = atile
+			break
+		}
+	}
 	if err := t.RemoveTile(from, tile); err != nil {
 		return err
 	}
 	return t.AddTile(to, tile)
 }
 
+// RemoveTile method removes the tile from the given key. The key sequence is
+// copied instead of shifted in place, so callers ranging over it are not
+// affected, and keys without tiles are deleted.
 func (t *TileGrid) RemoveTile(key string, tile IEntity) error {
 	if tiles, ok := t.tiles[key]; ok {
 		for i, atile := range tiles {
-			if atile == tile {
-				t.tiles[key] = append(t.tiles[key][:i], t.tiles[key][i+1:]...)
+			if isSameTile(atile, tile) {
+				if len(tiles) == 1 {
+					delete(t.tiles, key)
+				} else {
+					t.tiles[key] = append(tiles[:i:i], tiles[i+1:]...)
+				}
 				return nil
 			}
 		}
@@ -115,11 +164,35 @@ func (t *TileGrid) RemoveTile(key string, tile IEntity) error {
 	return nil
 }
 
-func (t *TileGrid) Update(args ...any) error {
-	for _, tiles := range t.tiles {
-		for _, tile := range tiles {
-			if result, ok := CheckUpdatable(tile); ok {
-				return result.Update(args...)
+// Submit method adds all tiles to the render queue. Tiles that are not
+// renderable are submitted to the world render layer, sorted by their tile
+// row.
+func (t *TileGrid) Submit(queue *RenderQueue) {
+	for _, key := range t.getSortedKeys() {
+		_, tileY := GetKeyStringToInt(key)
+		for _, tile := range t.tiles[key] {
+			if result, ok := CheckRenderable(tile); ok {
+				result.Submit(queue)
+			} else if result, ok := CheckDrawable(tile); ok {
+				sortY := float64((tileY + 1) * t.height)
+				queue.SubmitDrawable(RenderLayerWorld, 0, sortY, result)
+			}
+		}
+	}
+}
+
+// Update method updates all tiles once, in key order. Tiles are collected
+// before any update, because tiles completing a move change the grid while
+// it is updated.
+func (t *TileGrid) Update(ctx *UpdateContext) error {
+	var tiles []IEntity
+	for _, key := range t.getSortedKeys() {
+		tiles = append(tiles, t.tiles[key]...)
+	}
+	for _, tile := range tiles {
+		if result, ok := CheckUpdatable(tile); ok {
+			if err := result.Update(ctx); err != nil {
+				return err
 			}
 		}
 	}
@@ -59,9 +59,11 @@ func DecodeTileID(rawID uint32, tileWidth, tileHeight int, geoM *ebiten.GeoM) (u
 }
 
 type TilemapLayerJSON struct {
-	Data   []uint32 `json:"data"`
-	Width  int      `json:"width"`
-	Height int      `json:"height"`
+	Data        []uint32 `json:"data"`
+	Width       int      `json:"width"`
+	Height      int      `json:"height"`
+	renderLayer int
+	perTile     bool
 }
 
 type TilemapJSON struct {
@@ -106,19 +108,70 @@ func (t *TilemapJSON) GetTileSize() (int, int) {
 	return t.tileSheet.GetTileSize()
 }
 
-func (t *TilemapJSON) Draw(screen *ebiten.Image, camera *Camera) {
+// drawTile method draws the tile at the given index in the given layer.
+func (t *TilemapJSON) drawTile(screen *ebiten.Image, camera *Camera, layer *TilemapLayerJSON, index int) {
 	op := &ebiten.DrawImageOptions{}
-	for _, layer := range t.Layers {
+	w, h := t.tileSheet.GetTileSize()
+	id, _ := DecodeTileID(layer.Data[index], w, h, &op.GeoM)
+	tileImage := t.tileSheet.GetSpriteForID(int(id))
+	screenX := (index % layer.Width) * w
+	screenY := (index / layer.Width) * h
+	op.GeoM.Translate(float64(screenX), float64(screenY))
+	camera.Apply(&op.GeoM)
+	screen.DrawImage(tileImage, op)
+}
+
+// drawLayer method draws all tiles in the given layer.
+func (t *TilemapJSON) drawLayer(screen *ebiten.Image, camera *Camera, layer *TilemapLayerJSON) {
+	for index := range layer.Data {
+		t.drawTile(screen, camera, layer, index)
+	}
+}
+
+func (t *TilemapJSON) Draw(screen *ebiten.Image, camera *Camera) {
+	for i := range t.Layers {
+		t.drawLayer(screen, camera, &t.Layers[i])
+	}
+}
+
+// SetLayerRender method sets the render queue layer for the tilemap layer at
+// the given index. When perTile is true, every tile is submitted on its own,
+// so tiles are Y-sorted with actors in the same render layer, which allows
+// characters to walk behind and in front of tall tiles.
+func (t *TilemapJSON) SetLayerRender(index int, renderLayer int, perTile bool) *TilemapJSON {
+	if index >= 0 && index < len(t.Layers) {
+		t.Layers[index].renderLayer = renderLayer
+		t.Layers[index].perTile = perTile
+	}
+	return t
+}
+
+// Submit method adds tilemap layers to the render queue. Layers are in the
+// background render layer by default, keeping the tilemap layer order as
+// z-index. Tiles in per-tile layers are submitted with zero z-index, like
+// actors, so they are ordered with actors by their vertical position.
+func (t *TilemapJSON) Submit(queue *RenderQueue) {
+	_, tileHeight := t.GetTileSize()
+	for i := range t.Layers {
+		layer := &t.Layers[i]
+		if !layer.perTile {
+			queue.Submit(layer.renderLayer, float64(i), 0, func(screen *ebiten.Image, camera *Camera) {
+				t.drawLayer(screen, camera, layer)
+			})
+			continue
+		}
 		for index, data := range layer.Data {
-			w, h := t.tileSheet.GetTileSize()
-			id, _ := DecodeTileID(data, w, h, &op.GeoM)
-			tileImage := t.tileSheet.GetSpriteForID(int(id))
-			screenX := (index % layer.Width) * w
-			screenY := (index / layer.Width) * h
-			op.GeoM.Translate(float64(screenX), float64(screenY))
-			op.GeoM.Translate(float64(camera.X), float64(camera.Y))
-			screen.DrawImage(tileImage, op)
-			op.GeoM.Reset()
+			if GetSpriteID(data) == 0 {
+				// empty tile.
+				continue
+			}
+			sortY := float64((index/layer.Width + 1) * tileHeight)
+			queue.Submit(layer.renderLayer, 0, sortY, func(screen *ebiten.Image, camera *Camera) {
+				t.drawTile(screen, camera, layer, index)
+			})
 		}
 	}
 }
+
+var _ IDrawable = (*TilemapJSON)(nil)
+var _ IRenderable = (*TilemapJSON)(nil)
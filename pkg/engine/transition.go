@@ -0,0 +1,192 @@
+package engine
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/jrecuero/ebiplay/pkg/tools"
+)
+
+// ITransition interface defines the animation played when the scene manager
+// changes scenes. It draws the previous and the new scenes, already rendered
+// into images, on the screen.
+type ITransition interface {
+	Draw(screen, from, to *ebiten.Image)
+	IsFinished() bool
+	Reset()
+	Update(float64)
+}
+
+// Transition structure defines the common data for all transitions: the
+// duration, in seconds, and the easing curve applied to the progress.
+type Transition struct {
+	duration float64
+	elapsed  float64
+	ease     tools.EasingFunc
+}
+
+// NewTransition function creates a new Transition instance.
+func NewTransition(duration float64) *Transition {
+	return &Transition{
+		duration: duration,
+		ease:     tools.EaseLinear,
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Transition public methods
+// -----------------------------------------------------------------------------
+
+// GetProgress method returns the eased transition progress, between 0 and 1.
+func (t *Transition) GetProgress() float64 {
+	if t.duration <= 0 {
+		return 1
+	}
+	return t.ease(min(t.elapsed/t.duration, 1))
+}
+
+func (t *Transition) IsFinished() bool {
+	return t.elapsed >= t.duration
+}
+
+func (t *Transition) Reset() {
+	t.elapsed = 0
+}
+
+func (t *Transition) SetEase(ease tools.EasingFunc) *Transition {
+	t.ease = ease
+	return t
+}
+
+// Update method advances the transition by the given elapsed time in
+// seconds.
+func (t *Transition) Update(dt float64) {
+	t.elapsed += dt
+}
+
+// CrossfadeTransition structure defines a transition where the new scene
+// fades in over the previous one.
+type CrossfadeTransition struct {
+	*Transition
+}
+
+// NewCrossfadeTransition function creates a new CrossfadeTransition instance.
+func NewCrossfadeTransition(duration float64) *CrossfadeTransition {
+	return &CrossfadeTransition{
+		Transition: NewTransition(duration),
+	}
+}
+
+// -----------------------------------------------------------------------------
+// CrossfadeTransition public methods
+// -----------------------------------------------------------------------------
+
+func (t *CrossfadeTransition) Draw(screen, from, to *ebiten.Image) {
+	screen.DrawImage(from, nil)
+	op := &ebiten.DrawImageOptions{}
+	op.ColorScale.ScaleAlpha(float32(t.GetProgress()))
+	screen.DrawImage(to, op)
+}
+
+// FadeTransition structure defines a transition where the previous scene
+// fades out to a color, and then the new scene fades in from that color.
+type FadeTransition struct {
+	*Transition
+	color color.Color
+}
+
+// NewFadeTransition function creates a new FadeTransition instance.
+func NewFadeTransition(duration float64, clr color.Color) *FadeTransition {
+	return &FadeTransition{
+		Transition: NewTransition(duration),
+		color:      clr,
+	}
+}
+
+// -----------------------------------------------------------------------------
+// FadeTransition public methods
+// -----------------------------------------------------------------------------
+
+func (t *FadeTransition) Draw(screen, from, to *ebiten.Image) {
+	progress := t.GetProgress()
+	var amount float64
+	if progress < 0.5 {
+		screen.DrawImage(from, nil)
+		amount = progress * 2
+	} else {
+		screen.DrawImage(to, nil)
+		amount = (1 - progress) * 2
+	}
+	amount = math.Max(0, math.Min(amount, 1))
+	r, g, b, a := t.color.RGBA()
+	overlay := color.RGBA64{
+		R: uint16(float64(r) * amount),
+		G: uint16(float64(g) * amount),
+		B: uint16(float64(b) * amount),
+		A: uint16(float64(a) * amount),
+	}
+	bounds := screen.Bounds()
+	vector.DrawFilledRect(screen, float32(bounds.Min.X), float32(bounds.Min.Y), float32(bounds.Dx()), float32(bounds.Dy()), overlay, false)
+}
+
+// WipeDirection type defines the direction the new scene is revealed in a
+// wipe transition.
+type WipeDirection int
+
+const (
+	WipeLeft WipeDirection = iota
+	WipeRight
+	WipeUp
+	WipeDown
+)
+
+// WipeTransition structure defines a transition where the new scene is
+// revealed over the previous one moving an edge across the screen.
+type WipeTransition struct {
+	*Transition
+	direction WipeDirection
+}
+
+// NewWipeTransition function creates a new WipeTransition instance.
+func NewWipeTransition(duration float64, direction WipeDirection) *WipeTransition {
+	return &WipeTransition{
+		Transition: NewTransition(duration),
+		direction:  direction,
+	}
+}
+
+// -----------------------------------------------------------------------------
+// WipeTransition public methods
+// -----------------------------------------------------------------------------
+
+func (t *WipeTransition) Draw(screen, from, to *ebiten.Image) {
+	screen.DrawImage(from, nil)
+	bounds := to.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	progress := t.GetProgress()
+	var rect image.Rectangle
+	switch t.direction {
+	case WipeLeft:
+		rect = image.Rect(w-int(float64(w)*progress), 0, w, h)
+	case WipeRight:
+		rect = image.Rect(0, 0, int(float64(w)*progress), h)
+	case WipeUp:
+		rect = image.Rect(0, h-int(float64(h)*progress), w, h)
+	case WipeDown:
+		rect = image.Rect(0, 0, w, int(float64(h)*progress))
+	}
+	if rect.Empty() {
+		return
+	}
+	rect = rect.Add(bounds.Min)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(rect.Min.X-bounds.Min.X), float64(rect.Min.Y-bounds.Min.Y))
+	screen.DrawImage(to.SubImage(rect).(*ebiten.Image), op)
+}
+
+var _ ITransition = (*CrossfadeTransition)(nil)
+var _ ITransition = (*FadeTransition)(nil)
+var _ ITransition = (*WipeTransition)(nil)
@@ -0,0 +1,449 @@
+package engine
+
+import (
+	"slices"
+
+	"github.com/jrecuero/ebiplay/pkg/tools"
+)
+
+// RepeatForever is the repeat count used for tweens that never finish.
+const RepeatForever = -1
+
+// ITween interface defines any animation driven by elapsed time, in seconds.
+type ITween interface {
+	// IsFinished returns if the tween has been completed.
+	IsFinished() bool
+
+	// Reset sets the tween back to its initial state.
+	Reset()
+
+	// Update advances the tween by the given elapsed time and returns the
+	// time left over after the tween has finished.
+	Update(float64) float64
+}
+
+// Tween structure defines the interpolation of a float property from a start
+// value to an end value over time.
+//
+// The start value can be captured from a getter when the tween starts, which
+// allows tweens in a sequence to start from wherever the previous one left
+// the property.
+type Tween struct {
+	from, to   float64
+	getter     func() float64
+	setter     func(float64)
+	duration   float64
+	delay      float64
+	waited     float64
+	elapsed    float64
+	ease       tools.EasingFunc
+	repeat     int
+	iteration  int
+	yoyo       bool
+	started    bool
+	finished   bool
+	onComplete func()
+}
+
+// NewTween function creates a new Tween instance that interpolates from one
+// value to other calling the setter with the current value.
+func NewTween(from, to, duration float64, setter func(float64)) *Tween {
+	return &Tween{
+		from:     from,
+		to:       to,
+		setter:   setter,
+		duration: duration,
+		ease:     tools.EaseLinear,
+	}
+}
+
+// NewTweenTo function creates a new Tween instance that interpolates from the
+// value returned by the getter when the tween starts to the given value.
+func NewTweenTo(getter func() float64, setter func(float64), to, duration float64) *Tween {
+	tween := NewTween(0, to, duration, setter)
+	tween.getter = getter
+	return tween
+}
+
+// -----------------------------------------------------------------------------
+// Tween private methods
+// -----------------------------------------------------------------------------
+
+// apply method calls the setter with the value for the current elapsed time.
+func (t *Tween) apply() {
+	progress := 1.0
+	if t.duration > 0 {
+		progress = t.elapsed / t.duration
+	}
+	if t.yoyo && t.iteration%2 == 1 {
+		progress = 1 - progress
+	}
+	t.setter(tools.Lerp(t.from, t.to, t.ease(progress)))
+}
+
+// -----------------------------------------------------------------------------
+// Tween public methods
+// -----------------------------------------------------------------------------
+
+func (t *Tween) IsFinished() bool {
+	return t.finished
+}
+
+// OnComplete method sets the function called when the tween finishes.
+func (t *Tween) OnComplete(callback func()) *Tween {
+	t.onComplete = callback
+	return t
+}
+
+func (t *Tween) Reset() {
+	t.waited = 0
+	t.elapsed = 0
+	t.iteration = 0
+	t.started = false
+	t.finished = false
+}
+
+// SetDelay method sets the time to wait before the tween starts.
+func (t *Tween) SetDelay(delay float64) *Tween {
+	t.delay = delay
+	return t
+}
+
+func (t *Tween) SetEase(ease tools.EasingFunc) *Tween {
+	t.ease = ease
+	return t
+}
+
+// SetRepeat method sets the number of times the tween is played again after
+// the first time. RepeatForever can be used for endless tweens.
+func (t *Tween) SetRepeat(repeat int) *Tween {
+	t.repeat = repeat
+	return t
+}
+
+// SetYoyo method sets if every repetition goes back and forth instead of
+// always starting from the beginning.
+func (t *Tween) SetYoyo(yoyo bool) *Tween {
+	t.yoyo = yoyo
+	return t
+}
+
+func (t *Tween) Update(dt float64) float64 {
+	if t.finished {
+		return dt
+	}
+	if !t.started {
+		if t.waited+dt < t.delay {
+			t.waited += dt
+			return 0
+		}
+		dt -= t.delay - t.waited
+		t.waited = t.delay
+		t.started = true
+		if t.getter != nil {
+			t.from = t.getter()
+		}
+	}
+	t.elapsed += dt
+	for t.elapsed >= t.duration {
+		if t.repeat != RepeatForever && t.iteration >= t.repeat {
+			left := t.elapsed - t.duration
+			t.elapsed = t.duration
+			t.apply()
+			t.finished = true
+			if t.onComplete != nil {
+				t.onComplete()
+			}
+			return left
+		}
+		t.iteration++
+		if t.duration <= 0 {
+			break
+		}
+		t.elapsed -= t.duration
+	}
+	t.apply()
+	return 0
+}
+
+// TweenSequence structure defines a list of tweens played one after other.
+type TweenSequence struct {
+	tweens     []ITween
+	index      int
+	onComplete func()
+}
+
+// NewTweenSequence function creates a new TweenSequence instance.
+func NewTweenSequence(tweens ...ITween) *TweenSequence {
+	return &TweenSequence{
+		tweens: tweens,
+	}
+}
+
+// -----------------------------------------------------------------------------
+// TweenSequence public methods
+// -----------------------------------------------------------------------------
+
+// Add method appends a tween at the end of the sequence.
+func (s *TweenSequence) Add(tween ITween) *TweenSequence {
+	s.tweens = append(s.tweens, tween)
+	return s
+}
+
+// AddDelay method appends a wait time at the end of the sequence.
+func (s *TweenSequence) AddDelay(delay float64) *TweenSequence {
+	return s.Add(NewTweenDelay(delay))
+}
+
+func (s *TweenSequence) IsFinished() bool {
+	return s.index >= len(s.tweens)
+}
+
+// OnComplete method sets the function called when the last tween in the
+// sequence finishes.
+func (s *TweenSequence) OnComplete(callback func()) *TweenSequence {
+	s.onComplete = callback
+	return s
+}
+
+func (s *TweenSequence) Reset() {
+	s.index = 0
+	for _, tween := range s.tweens {
+		tween.Reset()
+	}
+}
+
+func (s *TweenSequence) Update(dt float64) float64 {
+	if s.IsFinished() {
+		return dt
+	}
+	for s.index < len(s.tweens) {
+		dt = s.tweens[s.index].Update(dt)
+		if !s.tweens[s.index].IsFinished() {
+			return 0
+		}
+		s.index++
+	}
+	if s.onComplete != nil {
+		s.onComplete()
+	}
+	return dt
+}
+
+// TweenGroup structure defines a list of tweens played at the same time. The
+// group finishes when all tweens have finished.
+type TweenGroup struct {
+	tweens     []ITween
+	finished   bool
+	onComplete func()
+}
+
+// NewTweenGroup function creates a new TweenGroup instance.
+func NewTweenGroup(tweens ...ITween) *TweenGroup {
+	return &TweenGroup{
+		tweens: tweens,
+	}
+}
+
+// -----------------------------------------------------------------------------
+// TweenGroup public methods
+// -----------------------------------------------------------------------------
+
+// Add method adds a tween to be played with the rest of tweens in the group.
+func (g *TweenGroup) Add(tween ITween) *TweenGroup {
+	g.tweens = append(g.tweens, tween)
+	return g
+}
+
+func (g *TweenGroup) IsFinished() bool {
+	return g.finished
+}
+
+// OnComplete method sets the function called when all tweens in the group
+// have finished.
+func (g *TweenGroup) OnComplete(callback func()) *TweenGroup {
+	g.onComplete = callback
+	return g
+}
+
+func (g *TweenGroup) Reset() {
+	g.finished = false
+	for _, tween := range g.tweens {
+		tween.Reset()
+	}
+}
+
+// SetEase method sets the easing curve for all tweens in the group.
+func (g *TweenGroup) SetEase(ease tools.EasingFunc) *TweenGroup {
+	for _, tween := range g.tweens {
+		if t, ok := tween.(*Tween); ok {
+			t.SetEase(ease)
+		}
+	}
+	return g
+}
+
+func (g *TweenGroup) Update(dt float64) float64 {
+	if g.finished {
+		return dt
+	}
+	left := dt
+	for _, tween := range g.tweens {
+		if !tween.IsFinished() {
+			left = min(left, tween.Update(dt))
+		}
+	}
+	for _, tween := range g.tweens {
+		if !tween.IsFinished() {
+			return 0
+		}
+	}
+	g.finished = true
+	if g.onComplete != nil {
+		g.onComplete()
+	}
+	return left
+}
+
+// TweenDelay structure defines a tween that only waits for the given time,
+// to be used inside sequences.
+type TweenDelay struct {
+	duration float64
+	elapsed  float64
+}
+
+// NewTweenDelay function creates a new TweenDelay instance.
+func NewTweenDelay(duration float64) *TweenDelay {
+	return &TweenDelay{
+		duration: duration,
+	}
+}
+
+// -----------------------------------------------------------------------------
+// TweenDelay public methods
+// -----------------------------------------------------------------------------
+
+func (d *TweenDelay) IsFinished() bool {
+	return d.elapsed >= d.duration
+}
+
+func (d *TweenDelay) Reset() {
+	d.elapsed = 0
+}
+
+func (d *TweenDelay) Update(dt float64) float64 {
+	d.elapsed += dt
+	if d.elapsed > d.duration {
+		return d.elapsed - d.duration
+	}
+	return 0
+}
+
+// TweenManager structure holds all running tweens and updates them every
+// tick. Finished tweens are removed.
+type TweenManager struct {
+	tweens []ITween
+}
+
+// NewTweenManager function creates a new TweenManager instance.
+func NewTweenManager() *TweenManager {
+	return &TweenManager{}
+}
+
+// -----------------------------------------------------------------------------
+// TweenManager public methods
+// -----------------------------------------------------------------------------
+
+// Add method starts running the given tween.
+func (m *TweenManager) Add(tween ITween) ITween {
+	m.tweens = append(m.tweens, tween)
+	return tween
+}
+
+// Clear method stops all running tweens.
+func (m *TweenManager) Clear() {
+	m.tweens = nil
+}
+
+func (m *TweenManager) Len() int {
+	return len(m.tweens)
+}
+
+// Remove method stops the given tween.
+func (m *TweenManager) Remove(tween ITween) {
+	for i, t := range m.tweens {
+		if t == tween {
+			m.tweens = append(m.tweens[:i], m.tweens[i+1:]...)
+			return
+		}
+	}
+}
+
+// Update method advances all running tweens by the given elapsed time in
+// seconds.
+func (m *TweenManager) Update(dt float64) {
+	// tweens are copied, because callbacks can add or remove tweens during
+	// the update. Added tweens start in the next tick, and removed tweens are
+	// not updated anymore.
+	tweens := append([]ITween(nil), m.tweens...)
+	for _, tween := range tweens {
+		if slices.Contains(m.tweens, tween) {
+			tween.Update(dt)
+		}
+	}
+	alive := m.tweens[:0]
+	for _, tween := range m.tweens {
+		if !tween.IsFinished() {
+			alive = append(alive, tween)
+		}
+	}
+	m.tweens = alive
+}
+
+// -----------------------------------------------------------------------------
+// Property tweens
+// -----------------------------------------------------------------------------
+
+// TweenAlphaTo function creates a tween that changes the actor opacity.
+func TweenAlphaTo(actor *Actor, alpha, duration float64) *Tween {
+	return NewTweenTo(actor.GetAlpha, func(v float64) { actor.SetAlpha(v) }, alpha, duration)
+}
+
+// TweenCameraTo function creates a tween that moves the camera offset.
+func TweenCameraTo(camera *Camera, x, y, duration float64) *TweenGroup {
+	return NewTweenGroup(
+		NewTweenTo(func() float64 { return camera.X }, func(v float64) { camera.X = v }, x, duration),
+		NewTweenTo(func() float64 { return camera.Y }, func(v float64) { camera.Y = v }, y, duration),
+	)
+}
+
+// TweenPosTo function creates a tween that moves the entity to the given
+// position.
+func TweenPosTo(entity IEntity, x, y, duration float64) *TweenGroup {
+	return NewTweenGroup(
+		NewTweenTo(entity.GetX, func(v float64) { entity.SetX(v) }, x, duration),
+		NewTweenTo(entity.GetY, func(v float64) { entity.SetY(v) }, y, duration),
+	)
+}
+
+// TweenScaleTo function creates a tween that changes the actor horizontal
+// and vertical scale to the given values.
+func TweenScaleTo(actor *Actor, scaleX, scaleY, duration float64) *TweenGroup {
+	getX := func() float64 {
+		x, _ := actor.GetScaleXY()
+		return x
+	}
+	getY := func() float64 {
+		_, y := actor.GetScaleXY()
+		return y
+	}
+	return NewTweenGroup(
+		NewTweenTo(getX, func(v float64) { actor.SetScaleXY(v, getY()) }, scaleX, duration),
+		NewTweenTo(getY, func(v float64) { actor.SetScaleXY(getX(), v) }, scaleY, duration),
+	)
+}
+
+var _ ITween = (*Tween)(nil)
+var _ ITween = (*TweenSequence)(nil)
+var _ ITween = (*TweenGroup)(nil)
+var _ ITween = (*TweenDelay)(nil)
@@ -1,7 +1,7 @@
 package engine
 
 type IUpdatable interface {
-	Update(...any) error
+	Update(*UpdateContext) error
 }
 
 func CheckUpdatable(obj any) (IUpdatable, bool) {
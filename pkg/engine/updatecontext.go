@@ -0,0 +1,51 @@
+package engine
+
+import "image"
+
+// UpdateContext structure defines the frame information passed to every
+// update. The same context is reused for every frame, and new fields can be
+// added without changing any Update signature.
+//
+// Fields:
+//   - Delta: elapsed time, in seconds, since the previous update.
+//   - Tick: number of the current update, starting at one.
+//   - Input: input state for the current update.
+//   - Bounds: world boundary, usually the tilemap size in pixels.
+//   - World: registry with all objects in the world, it can be nil.
+//   - Collision: collision world actors move against, it can be nil.
+type UpdateContext struct {
+	Delta     float64
+	Tick      uint64
+	Input     IInput
+	Bounds    image.Rectangle
+	World     *Registry
+	Collision *CollisionWorld
+}
+
+// NewUpdateContext function creates a new UpdateContext instance with the
+// given world boundary and registry, reading input from ebiten.
+func NewUpdateContext(bounds image.Rectangle, world *Registry) *UpdateContext {
+	return &UpdateContext{
+		Input:  NewEbitenInput(),
+		Bounds: bounds,
+		World:  world,
+	}
+}
+
+// -----------------------------------------------------------------------------
+// UpdateContext public methods
+// -----------------------------------------------------------------------------
+
+// Advance method starts a new frame with the given elapsed time in seconds.
+// It has to be called once at the beginning of every game update.
+func (c *UpdateContext) Advance(delta float64) *UpdateContext {
+	c.Tick++
+	c.Delta = delta
+	return c
+}
+
+// GetBoundsSize method returns the world boundary width and height in
+// pixels.
+func (c *UpdateContext) GetBoundsSize() (float64, float64) {
+	return float64(c.Bounds.Dx()), float64(c.Bounds.Dy())
+}
@@ -0,0 +1,189 @@
+// easing.go contains easing curves used to interpolate values over time.
+package tools
+
+import "math"
+
+// EasingFunc type defines an easing curve. It maps a linear progress between
+// zero and one to an eased progress, which is zero at the start and one at the
+// end, but it can go beyond those limits in between (back and elastic).
+type EasingFunc func(float64) float64
+
+// -----------------------------------------------------------------------------
+// Public functions
+// -----------------------------------------------------------------------------
+
+// EaseInBack function starts moving backwards before going to the end.
+func EaseInBack(t float64) float64 {
+	const c1 = 1.70158
+	const c3 = c1 + 1
+	return c3*t*t*t - c1*t*t
+}
+
+// EaseInBounce function bounces at the start.
+func EaseInBounce(t float64) float64 {
+	return 1 - EaseOutBounce(1-t)
+}
+
+// EaseInCubic function accelerates from zero velocity.
+func EaseInCubic(t float64) float64 {
+	return t * t * t
+}
+
+// EaseInElastic function oscillates at the start.
+func EaseInElastic(t float64) float64 {
+	if t == 0 || t == 1 {
+		return t
+	}
+	const c4 = (2 * math.Pi) / 3
+	return -math.Pow(2, 10*t-10) * math.Sin((t*10-10.75)*c4)
+}
+
+// EaseInExpo function accelerates exponentially from zero velocity.
+func EaseInExpo(t float64) float64 {
+	if t == 0 {
+		return 0
+	}
+	return math.Pow(2, 10*t-10)
+}
+
+// EaseInOutBack function goes backwards at the start and overshoots at the
+// end.
+func EaseInOutBack(t float64) float64 {
+	const c1 = 1.70158
+	const c2 = c1 * 1.525
+	if t < 0.5 {
+		return (math.Pow(2*t, 2) * ((c2+1)*2*t - c2)) / 2
+	}
+	return (math.Pow(2*t-2, 2)*((c2+1)*(t*2-2)+c2) + 2) / 2
+}
+
+// EaseInOutBounce function bounces at the start and at the end.
+func EaseInOutBounce(t float64) float64 {
+	if t < 0.5 {
+		return (1 - EaseOutBounce(1-2*t)) / 2
+	}
+	return (1 + EaseOutBounce(2*t-1)) / 2
+}
+
+// EaseInOutCubic function accelerates until halfway and then decelerates.
+func EaseInOutCubic(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	return 1 - math.Pow(-2*t+2, 3)/2
+}
+
+// EaseInOutElastic function oscillates at the start and at the end.
+func EaseInOutElastic(t float64) float64 {
+	if t == 0 || t == 1 {
+		return t
+	}
+	const c5 = (2 * math.Pi) / 4.5
+	if t < 0.5 {
+		return -(math.Pow(2, 20*t-10) * math.Sin((20*t-11.125)*c5)) / 2
+	}
+	return (math.Pow(2, -20*t+10)*math.Sin((20*t-11.125)*c5))/2 + 1
+}
+
+// EaseInOutExpo function accelerates and decelerates exponentially.
+func EaseInOutExpo(t float64) float64 {
+	if t == 0 || t == 1 {
+		return t
+	}
+	if t < 0.5 {
+		return math.Pow(2, 20*t-10) / 2
+	}
+	return (2 - math.Pow(2, -20*t+10)) / 2
+}
+
+// EaseInOutQuad function accelerates until halfway and then decelerates.
+func EaseInOutQuad(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return 1 - math.Pow(-2*t+2, 2)/2
+}
+
+// EaseInOutSine function accelerates and decelerates following a sine curve.
+func EaseInOutSine(t float64) float64 {
+	return -(math.Cos(math.Pi*t) - 1) / 2
+}
+
+// EaseInQuad function accelerates from zero velocity.
+func EaseInQuad(t float64) float64 {
+	return t * t
+}
+
+// EaseInSine function accelerates following a sine curve.
+func EaseInSine(t float64) float64 {
+	return 1 - math.Cos((t*math.Pi)/2)
+}
+
+// EaseLinear function does not apply any easing.
+func EaseLinear(t float64) float64 {
+	return t
+}
+
+// EaseOutBack function overshoots the end and then comes back.
+func EaseOutBack(t float64) float64 {
+	const c1 = 1.70158
+	const c3 = c1 + 1
+	return 1 + c3*math.Pow(t-1, 3) + c1*math.Pow(t-1, 2)
+}
+
+// EaseOutBounce function bounces at the end.
+func EaseOutBounce(t float64) float64 {
+	const n1 = 7.5625
+	const d1 = 2.75
+	switch {
+	case t < 1/d1:
+		return n1 * t * t
+	case t < 2/d1:
+		t -= 1.5 / d1
+		return n1*t*t + 0.75
+	case t < 2.5/d1:
+		t -= 2.25 / d1
+		return n1*t*t + 0.9375
+	default:
+		t -= 2.625 / d1
+		return n1*t*t + 0.984375
+	}
+}
+
+// EaseOutCubic function decelerates to zero velocity.
+func EaseOutCubic(t float64) float64 {
+	return 1 - math.Pow(1-t, 3)
+}
+
+// EaseOutElastic function oscillates at the end.
+func EaseOutElastic(t float64) float64 {
+	if t == 0 || t == 1 {
+		return t
+	}
+	const c4 = (2 * math.Pi) / 3
+	return math.Pow(2, -10*t)*math.Sin((t*10-0.75)*c4) + 1
+}
+
+// EaseOutExpo function decelerates exponentially to zero velocity.
+func EaseOutExpo(t float64) float64 {
+	if t == 1 {
+		return 1
+	}
+	return 1 - math.Pow(2, -10*t)
+}
+
+// EaseOutQuad function decelerates to zero velocity.
+func EaseOutQuad(t float64) float64 {
+	return 1 - (1-t)*(1-t)
+}
+
+// EaseOutSine function decelerates following a sine curve.
+func EaseOutSine(t float64) float64 {
+	return math.Sin((t * math.Pi) / 2)
+}
+
+// Lerp function returns the linear interpolation between a and b for the
+// given progress.
+func Lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
@@ -0,0 +1,49 @@
+package tools_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jrecuero/ebiplay/pkg/tools"
+)
+
+func TestEasingLimits(t *testing.T) {
+	easings := map[string]tools.EasingFunc{
+		"linear":       tools.EaseLinear,
+		"inQuad":       tools.EaseInQuad,
+		"outQuad":      tools.EaseOutQuad,
+		"inOutQuad":    tools.EaseInOutQuad,
+		"inCubic":      tools.EaseInCubic,
+		"outCubic":     tools.EaseOutCubic,
+		"inOutCubic":   tools.EaseInOutCubic,
+		"inSine":       tools.EaseInSine,
+		"outSine":      tools.EaseOutSine,
+		"inOutSine":    tools.EaseInOutSine,
+		"inExpo":       tools.EaseInExpo,
+		"outExpo":      tools.EaseOutExpo,
+		"inOutExpo":    tools.EaseInOutExpo,
+		"inBack":       tools.EaseInBack,
+		"outBack":      tools.EaseOutBack,
+		"inOutBack":    tools.EaseInOutBack,
+		"inElastic":    tools.EaseInElastic,
+		"outElastic":   tools.EaseOutElastic,
+		"inOutElastic": tools.EaseInOutElastic,
+		"inBounce":     tools.EaseInBounce,
+		"outBounce":    tools.EaseOutBounce,
+		"inOutBounce":  tools.EaseInOutBounce,
+	}
+	for name, ease := range easings {
+		if got := ease(0); math.Abs(got) > 1e-9 {
+			t.Errorf("%s(0) = %f, expected 0", name, got)
+		}
+		if got := ease(1); math.Abs(got-1) > 1e-9 {
+			t.Errorf("%s(1) = %f, expected 1", name, got)
+		}
+	}
+}
+
+func TestLerp(t *testing.T) {
+	if got := tools.Lerp(10, 20, 0.25); got != 12.5 {
+		t.Errorf("Lerp(10, 20, 0.25) = %f, expected 12.5", got)
+	}
+}
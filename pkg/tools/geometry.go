@@ -0,0 +1,141 @@
+// geometry.go contains float geometry used for collision detection.
+package tools
+
+import (
+	"image"
+	"math"
+)
+
+// Epsilon is the tolerance used in geometry comparisons, so edges that touch
+// because of float rounding are not reported as overlapping.
+const Epsilon = 1e-6
+
+// Rect structure defines an axis aligned rectangle with float precision.
+type Rect struct {
+	X, Y float64
+	W, H float64
+}
+
+// NewRect function creates a new Rect instance.
+func NewRect(x, y, w, h float64) Rect {
+	return Rect{X: x, Y: y, W: w, H: h}
+}
+
+// NewRectFromImage function creates a new Rect instance with the same area as
+// the given integer rectangle.
+func NewRectFromImage(rect image.Rectangle) Rect {
+	return Rect{
+		X: float64(rect.Min.X),
+		Y: float64(rect.Min.Y),
+		W: float64(rect.Dx()),
+		H: float64(rect.Dy()),
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Rect public methods
+// -----------------------------------------------------------------------------
+
+// Bottom method returns the rectangle bottom edge.
+func (r Rect) Bottom() float64 {
+	return r.Y + r.H
+}
+
+// Center method returns the rectangle center point.
+func (r Rect) Center() (float64, float64) {
+	return r.X + r.W/2, r.Y + r.H/2
+}
+
+// Contains method returns if the point is inside the rectangle.
+func (r Rect) Contains(x, y float64) bool {
+	return x >= r.X && x < r.Right() && y >= r.Y && y < r.Bottom()
+}
+
+// IsEmpty method returns if the rectangle has no area.
+func (r Rect) IsEmpty() bool {
+	return r.W <= 0 || r.H <= 0
+}
+
+// Overlaps method returns if both rectangles share some area. Rectangles that
+// only touch at their edges do not overlap.
+func (r Rect) Overlaps(other Rect) bool {
+	return r.X < other.Right()-Epsilon && other.X < r.Right()-Epsilon &&
+		r.Y < other.Bottom()-Epsilon && other.Y < r.Bottom()-Epsilon
+}
+
+// Right method returns the rectangle right edge.
+func (r Rect) Right() float64 {
+	return r.X + r.W
+}
+
+// ToImage method returns the smallest integer rectangle that contains the
+// rectangle.
+func (r Rect) ToImage() image.Rectangle {
+	return image.Rect(int(math.Floor(r.X)), int(math.Floor(r.Y)), int(math.Ceil(r.Right())), int(math.Ceil(r.Bottom())))
+}
+
+// Translate method returns the rectangle moved by the given delta.
+func (r Rect) Translate(dx, dy float64) Rect {
+	return Rect{X: r.X + dx, Y: r.Y + dy, W: r.W, H: r.H}
+}
+
+// Union method returns the smallest rectangle that contains both rectangles.
+func (r Rect) Union(other Rect) Rect {
+	x, y := math.Min(r.X, other.X), math.Min(r.Y, other.Y)
+	return Rect{
+		X: x,
+		Y: y,
+		W: math.Max(r.Right(), other.Right()) - x,
+		H: math.Max(r.Bottom(), other.Bottom()) - y,
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Private functions
+// -----------------------------------------------------------------------------
+
+// sweepAxis function returns the entry and exit times, as a fraction of the
+// move, for one axis. Intervals that do not overlap and do not move never
+// enter.
+func sweepAxis(minA, maxA, minB, maxB, delta float64) (float64, float64) {
+	if delta == 0 {
+		if maxA <= minB+Epsilon || minA >= maxB-Epsilon {
+			return math.Inf(1), math.Inf(-1)
+		}
+		return math.Inf(-1), math.Inf(1)
+	}
+	var entry, exit float64
+	if delta > 0 {
+		entry, exit = minB-maxA, maxB-minA
+	} else {
+		entry, exit = maxB-minA, minB-maxA
+	}
+	// rectangles touching because of float rounding are handled as touching.
+	if math.Abs(entry) < Epsilon {
+		entry = 0
+	}
+	return entry / delta, exit / delta
+}
+
+// -----------------------------------------------------------------------------
+// Public functions
+// -----------------------------------------------------------------------------
+
+// SweepAABB function moves the rectangle a by the given delta against the
+// static rectangle b, and returns the time of impact, as a fraction of the
+// move between 0 and 1, and the contact normal on b. It returns false if the
+// rectangles do not collide during the move, or if they were already
+// overlapping at the start, so overlapping rectangles can separate.
+func SweepAABB(a Rect, dx, dy float64, b Rect) (float64, float64, float64, bool) {
+	xEntry, xExit := sweepAxis(a.X, a.Right(), b.X, b.Right(), dx)
+	yEntry, yExit := sweepAxis(a.Y, a.Bottom(), b.Y, b.Bottom(), dy)
+	entry := math.Max(xEntry, yEntry)
+	exit := math.Min(xExit, yExit)
+	if entry > exit || entry < 0 || entry > 1 || (dx == 0 && dy == 0) {
+		return 1, 0, 0, false
+	}
+	if xEntry > yEntry {
+		return entry, -math.Copysign(1, dx), 0, true
+	}
+	return entry, 0, -math.Copysign(1, dy), true
+}
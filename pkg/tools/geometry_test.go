@@ -0,0 +1,49 @@
+package tools_test
+
+import (
+	"testing"
+
+	"github.com/jrecuero/ebiplay/pkg/tools"
+)
+
+func TestSweepAABB(t *testing.T) {
+	wall := tools.NewRect(20, 0, 10, 100)
+	tests := []struct {
+		name   string
+		rect   tools.Rect
+		dx, dy float64
+		hit    bool
+		time   float64
+		nx, ny float64
+	}{
+		{"hit", tools.NewRect(0, 10, 10, 10), 20, 0, true, 0.5, -1, 0},
+		{"tunnel", tools.NewRect(0, 10, 10, 10), 100, 0, true, 0.1, -1, 0},
+		{"miss", tools.NewRect(0, 10, 10, 10), 5, 0, false, 1, 0, 0},
+		{"away", tools.NewRect(0, 10, 10, 10), -20, 0, false, 1, 0, 0},
+		{"touching", tools.NewRect(10, 10, 10, 10), 5, 0, true, 0, -1, 0},
+		{"slide", tools.NewRect(10, 10, 10, 10), 0, 50, false, 1, 0, 0},
+		{"overlapping", tools.NewRect(15, 10, 10, 10), 5, 0, false, 1, 0, 0},
+		{"from right", tools.NewRect(40, 10, 10, 10), -20, 0, true, 0.5, 1, 0},
+		{"from top", tools.NewRect(20, -20, 10, 10), 0, 20, true, 0.5, 0, -1},
+	}
+	for _, test := range tests {
+		time, nx, ny, hit := tools.SweepAABB(test.rect, test.dx, test.dy, wall)
+		if hit != test.hit {
+			t.Errorf("%s: hit = %t, expected %t", test.name, hit, test.hit)
+			continue
+		}
+		if time != test.time || nx != test.nx || ny != test.ny {
+			t.Errorf("%s: (%f, %f, %f), expected (%f, %f, %f)", test.name, time, nx, ny, test.time, test.nx, test.ny)
+		}
+	}
+}
+
+func TestRectOverlaps(t *testing.T) {
+	a := tools.NewRect(0, 0, 10, 10)
+	if !a.Overlaps(tools.NewRect(5, 5, 10, 10)) {
+		t.Errorf("rectangles expected to overlap")
+	}
+	if a.Overlaps(tools.NewRect(10, 0, 10, 10)) {
+		t.Errorf("touching rectangles not expected to overlap")
+	}
+}
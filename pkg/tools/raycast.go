@@ -0,0 +1,96 @@
+// raycast.go contains ray intersection tests for rectangles and shapes.
+package tools
+
+import "math"
+
+// -----------------------------------------------------------------------------
+// Private functions
+// -----------------------------------------------------------------------------
+
+// raycastCircle function intersects the ray with the circle with the given
+// center and radius.
+func raycastCircle(origin, dir Vec, maxDistance float64, center Vec, radius float64) (float64, Vec, bool) {
+	m := origin.Sub(center)
+	b := m.Dot(dir)
+	c := m.Dot(m) - radius*radius
+	if c > 0 && b > 0 {
+		return 0, Vec{}, false
+	}
+	discriminant := b*b - c
+	if discriminant < 0 {
+		return 0, Vec{}, false
+	}
+	t := -b - math.Sqrt(discriminant)
+	if t < 0 {
+		return 0, Vec{}, true
+	}
+	if t > maxDistance {
+		return 0, Vec{}, false
+	}
+	return t, origin.Add(dir.Scale(t)).Sub(center).Normalize(), true
+}
+
+// raycastPolygon function intersects the ray with the convex polygon with
+// the given points, clipping the ray against every edge.
+func raycastPolygon(origin, dir Vec, maxDistance float64, points []Vec) (float64, Vec, bool) {
+	if len(points) < 3 {
+		return 0, Vec{}, false
+	}
+	var centroid Vec
+	for _, point := range points {
+		centroid = centroid.Add(point)
+	}
+	centroid = centroid.Scale(1 / float64(len(points)))
+	enter, exit := 0.0, maxDistance
+	var normal Vec
+	for i, point := range points {
+		next := points[(i+1)%len(points)]
+		edgeNormal := next.Sub(point).Perp().Normalize()
+		if edgeNormal == (Vec{}) {
+			continue
+		}
+		if point.Sub(centroid).Dot(edgeNormal) < 0 {
+			edgeNormal = edgeNormal.Scale(-1)
+		}
+		distance := edgeNormal.Dot(point.Sub(origin))
+		speed := edgeNormal.Dot(dir)
+		if speed == 0 {
+			if distance < 0 {
+				return 0, Vec{}, false
+			}
+			continue
+		}
+		t := distance / speed
+		if speed < 0 {
+			if t > enter {
+				enter, normal = t, edgeNormal
+			}
+		} else {
+			exit = math.Min(exit, t)
+		}
+		if enter > exit {
+			return 0, Vec{}, false
+		}
+	}
+	return enter, normal, true
+}
+
+// -----------------------------------------------------------------------------
+// Public functions
+// -----------------------------------------------------------------------------
+
+// RaycastRect function intersects the ray from origin, along the unit
+// direction, with the rectangle, up to the given distance. It returns the
+// distance to the hit and the normal on the rectangle at the hit point. Rays
+// starting inside the rectangle hit at distance zero, with no normal.
+func RaycastRect(origin, dir Vec, maxDistance float64, rect Rect) (float64, Vec, bool) {
+	return raycastPolygon(origin, dir, maxDistance, NewBox(rect.X, rect.Y, rect.W, rect.H).Points)
+}
+
+// RaycastShape function intersects the ray from origin, along the unit
+// direction, with the shape, up to the given distance. It returns the
+// distance to the hit and the normal on the shape at the hit point. Rays
+// starting inside the shape hit at distance zero, with no normal.
+func RaycastShape(origin, dir Vec, maxDistance float64, shape IShape) (float64, Vec, bool) {
+	return shape.raycast(origin, dir, maxDistance)
+}
@@ -0,0 +1,40 @@
+package tools_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jrecuero/ebiplay/pkg/tools"
+)
+
+func TestRaycastShape(t *testing.T) {
+	right := tools.Vec{X: 1, Y: 0}
+	tests := []struct {
+		name     string
+		shape    tools.IShape
+		origin   tools.Vec
+		hit      bool
+		distance float64
+		normal   tools.Vec
+	}{
+		{"box", tools.NewBox(10, -5, 10, 10), tools.Vec{}, true, 10, tools.Vec{X: -1, Y: 0}},
+		{"box miss", tools.NewBox(10, 5, 10, 10), tools.Vec{}, false, 0, tools.Vec{}},
+		{"box far", tools.NewBox(110, -5, 10, 10), tools.Vec{}, false, 0, tools.Vec{}},
+		{"box inside", tools.NewBox(-5, -5, 10, 10), tools.Vec{}, true, 0, tools.Vec{}},
+		{"box behind", tools.NewBox(-20, -5, 10, 10), tools.Vec{}, false, 0, tools.Vec{}},
+		{"circle", tools.NewCircle(20, 0, 5), tools.Vec{}, true, 15, tools.Vec{X: -1, Y: 0}},
+		{"circle miss", tools.NewCircle(20, 10, 5), tools.Vec{}, false, 0, tools.Vec{}},
+		{"capsule side", tools.NewCapsule(20, -10, 20, 10, 5), tools.Vec{}, true, 15, tools.Vec{X: -1, Y: 0}},
+		{"capsule end", tools.NewCapsule(20, 0, 40, 0, 5), tools.Vec{}, true, 15, tools.Vec{X: -1, Y: 0}},
+	}
+	for _, test := range tests {
+		distance, normal, hit := tools.RaycastShape(test.origin, right, 100, test.shape)
+		if hit != test.hit {
+			t.Errorf("%s: hit = %t, expected %t", test.name, hit, test.hit)
+			continue
+		}
+		if math.Abs(distance-test.distance) > tools.Epsilon || math.Abs(normal.X-test.normal.X) > tools.Epsilon || math.Abs(normal.Y-test.normal.Y) > tools.Epsilon {
+			t.Errorf("%s: (%f, %v), expected (%f, %v)", test.name, distance, normal, test.distance, test.normal)
+		}
+	}
+}
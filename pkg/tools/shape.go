@@ -0,0 +1,410 @@
+// shape.go contains convex collision shapes and the separating axis test.
+package tools
+
+import "math"
+
+// Vec structure defines a 2D vector with float precision.
+type Vec struct {
+	X, Y float64
+}
+
+// -----------------------------------------------------------------------------
+// Vec public methods
+// -----------------------------------------------------------------------------
+
+func (v Vec) Add(other Vec) Vec {
+	return Vec{v.X + other.X, v.Y + other.Y}
+}
+
+func (v Vec) Dot(other Vec) float64 {
+	return v.X*other.X + v.Y*other.Y
+}
+
+func (v Vec) Len() float64 {
+	return math.Hypot(v.X, v.Y)
+}
+
+// Normalize method returns the vector with length one, or the zero vector
+// when the vector has no length.
+func (v Vec) Normalize() Vec {
+	length := v.Len()
+	if length < Epsilon {
+		return Vec{}
+	}
+	return Vec{v.X / length, v.Y / length}
+}
+
+// Perp method returns the vector rotated 90 degrees.
+func (v Vec) Perp() Vec {
+	return Vec{-v.Y, v.X}
+}
+
+// Rotate method returns the vector rotated by the given angle in radians.
+func (v Vec) Rotate(angle float64) Vec {
+	sin, cos := math.Sincos(angle)
+	return Vec{v.X*cos - v.Y*sin, v.X*sin + v.Y*cos}
+}
+
+func (v Vec) Scale(factor float64) Vec {
+	return Vec{v.X * factor, v.Y * factor}
+}
+
+func (v Vec) Sub(other Vec) Vec {
+	return Vec{v.X - other.X, v.Y - other.Y}
+}
+
+// IShape interface defines a convex shape for collision detection. Shapes are
+// immutable, and Transform returns a new shape scaled, rotated around the
+// origin and translated, in that order.
+//
+// Every shape is defined by its core, points for polygons, a point for
+// circles and a segment for capsules, expanded by its radius.
+type IShape interface {
+	GetBounds() Rect
+	Transform(x, y, rotation, scale float64) IShape
+	getAxes() []Vec
+	getCore() []Vec
+	getRadius() float64
+	project(Vec) (float64, float64)
+	raycast(Vec, Vec, float64) (float64, Vec, bool)
+}
+
+// Circle structure defines a circle shape.
+type Circle struct {
+	Center Vec
+	Radius float64
+}
+
+// NewCircle function creates a new Circle instance.
+func NewCircle(x, y, radius float64) *Circle {
+	return &Circle{
+		Center: Vec{x, y},
+		Radius: radius,
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Circle private methods
+// -----------------------------------------------------------------------------
+
+func (c *Circle) getAxes() []Vec {
+	return nil
+}
+
+func (c *Circle) getCore() []Vec {
+	return []Vec{c.Center}
+}
+
+func (c *Circle) getRadius() float64 {
+	return c.Radius
+}
+
+func (c *Circle) project(axis Vec) (float64, float64) {
+	center := c.Center.Dot(axis)
+	return center - c.Radius, center + c.Radius
+}
+
+func (c *Circle) raycast(origin, dir Vec, maxDistance float64) (float64, Vec, bool) {
+	return raycastCircle(origin, dir, maxDistance, c.Center, c.Radius)
+}
+
+// -----------------------------------------------------------------------------
+// Circle public methods
+// -----------------------------------------------------------------------------
+
+func (c *Circle) GetBounds() Rect {
+	return NewRect(c.Center.X-c.Radius, c.Center.Y-c.Radius, 2*c.Radius, 2*c.Radius)
+}
+
+func (c *Circle) Transform(x, y, rotation, scale float64) IShape {
+	center := c.Center.Scale(scale).Rotate(rotation).Add(Vec{x, y})
+	return &Circle{Center: center, Radius: c.Radius * math.Abs(scale)}
+}
+
+// Capsule structure defines a capsule shape, a segment expanded by a radius.
+type Capsule struct {
+	A, B   Vec
+	Radius float64
+}
+
+// NewCapsule function creates a new Capsule instance with the given segment
+// end points and radius.
+func NewCapsule(x1, y1, x2, y2, radius float64) *Capsule {
+	return &Capsule{
+		A:      Vec{x1, y1},
+		B:      Vec{x2, y2},
+		Radius: radius,
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Capsule private methods
+// -----------------------------------------------------------------------------
+
+func (c *Capsule) getAxes() []Vec {
+	normal := c.B.Sub(c.A).Perp().Normalize()
+	if normal == (Vec{}) {
+		return nil
+	}
+	return []Vec{normal}
+}
+
+func (c *Capsule) getCore() []Vec {
+	return []Vec{c.A, c.B}
+}
+
+func (c *Capsule) getRadius() float64 {
+	return c.Radius
+}
+
+func (c *Capsule) project(axis Vec) (float64, float64) {
+	a, b := c.A.Dot(axis), c.B.Dot(axis)
+	return math.Min(a, b) - c.Radius, math.Max(a, b) + c.Radius
+}
+
+// raycast method intersects the ray with both capsule end circles and the box
+// between them, and returns the closest hit.
+func (c *Capsule) raycast(origin, dir Vec, maxDistance float64) (float64, Vec, bool) {
+	bestT, bestNormal, hit := maxDistance, Vec{}, false
+	check := func(t float64, normal Vec, ok bool) {
+		if ok && (!hit || t < bestT) {
+			bestT, bestNormal, hit = t, normal, true
+		}
+	}
+	check(raycastCircle(origin, dir, maxDistance, c.A, c.Radius))
+	check(raycastCircle(origin, dir, maxDistance, c.B, c.Radius))
+	if offset := c.B.Sub(c.A).Perp().Normalize().Scale(c.Radius); offset != (Vec{}) {
+		box := []Vec{c.A.Add(offset), c.B.Add(offset), c.B.Sub(offset), c.A.Sub(offset)}
+		check(raycastPolygon(origin, dir, maxDistance, box))
+	}
+	return bestT, bestNormal, hit
+}
+
+// -----------------------------------------------------------------------------
+// Capsule public methods
+// -----------------------------------------------------------------------------
+
+func (c *Capsule) GetBounds() Rect {
+	minX, minY := math.Min(c.A.X, c.B.X)-c.Radius, math.Min(c.A.Y, c.B.Y)-c.Radius
+	maxX, maxY := math.Max(c.A.X, c.B.X)+c.Radius, math.Max(c.A.Y, c.B.Y)+c.Radius
+	return NewRect(minX, minY, maxX-minX, maxY-minY)
+}
+
+func (c *Capsule) Transform(x, y, rotation, scale float64) IShape {
+	offset := Vec{x, y}
+	return &Capsule{
+		A:      c.A.Scale(scale).Rotate(rotation).Add(offset),
+		B:      c.B.Scale(scale).Rotate(rotation).Add(offset),
+		Radius: c.Radius * math.Abs(scale),
+	}
+}
+
+// Polygon structure defines a convex polygon shape. Points can be given
+// clockwise or counterclockwise.
+type Polygon struct {
+	Points []Vec
+}
+
+// NewPolygon function creates a new Polygon instance with the given convex
+// polygon points.
+func NewPolygon(points ...Vec) *Polygon {
+	return &Polygon{
+		Points: points,
+	}
+}
+
+// NewBox function creates a new axis aligned box Polygon instance with the
+// given top-left corner and size.
+func NewBox(x, y, w, h float64) *Polygon {
+	return NewPolygon(Vec{x, y}, Vec{x + w, y}, Vec{x + w, y + h}, Vec{x, y + h})
+}
+
+// NewOrientedBox function creates a new box Polygon instance with the given
+// center and size, rotated around its center by the given angle in radians.
+func NewOrientedBox(cx, cy, w, h, rotation float64) *Polygon {
+	center := Vec{cx, cy}
+	points := []Vec{{-w / 2, -h / 2}, {w / 2, -h / 2}, {w / 2, h / 2}, {-w / 2, h / 2}}
+	for i, point := range points {
+		points[i] = point.Rotate(rotation).Add(center)
+	}
+	return NewPolygon(points...)
+}
+
+// -----------------------------------------------------------------------------
+// Polygon private methods
+// -----------------------------------------------------------------------------
+
+// getAxes method returns the normal for every polygon edge.
+func (p *Polygon) getAxes() []Vec {
+	var axes []Vec
+	for i, point := range p.Points {
+		next := p.Points[(i+1)%len(p.Points)]
+		if normal := next.Sub(point).Perp().Normalize(); normal != (Vec{}) {
+			axes = append(axes, normal)
+		}
+	}
+	return axes
+}
+
+func (p *Polygon) getCore() []Vec {
+	return p.Points
+}
+
+func (p *Polygon) getRadius() float64 {
+	return 0
+}
+
+func (p *Polygon) project(axis Vec) (float64, float64) {
+	minValue, maxValue := math.Inf(1), math.Inf(-1)
+	for _, point := range p.Points {
+		value := point.Dot(axis)
+		minValue, maxValue = math.Min(minValue, value), math.Max(maxValue, value)
+	}
+	return minValue, maxValue
+}
+
+func (p *Polygon) raycast(origin, dir Vec, maxDistance float64) (float64, Vec, bool) {
+	return raycastPolygon(origin, dir, maxDistance, p.Points)
+}
+
+// -----------------------------------------------------------------------------
+// Polygon public methods
+// -----------------------------------------------------------------------------
+
+func (p *Polygon) GetBounds() Rect {
+	if len(p.Points) == 0 {
+		return Rect{}
+	}
+	minX, maxX := p.project(Vec{1, 0})
+	minY, maxY := p.project(Vec{0, 1})
+	return NewRect(minX, minY, maxX-minX, maxY-minY)
+}
+
+func (p *Polygon) Transform(x, y, rotation, scale float64) IShape {
+	offset := Vec{x, y}
+	points := make([]Vec, len(p.Points))
+	for i, point := range p.Points {
+		points[i] = point.Scale(scale).Rotate(rotation).Add(offset)
+	}
+	return NewPolygon(points...)
+}
+
+// -----------------------------------------------------------------------------
+// Private functions
+// -----------------------------------------------------------------------------
+
+// closestOnSegment function returns the point in the segment closest to the
+// given point.
+func closestOnSegment(a, b, point Vec) Vec {
+	segment := b.Sub(a)
+	length := segment.Dot(segment)
+	if length < Epsilon {
+		return a
+	}
+	t := math.Max(0, math.Min(1, point.Sub(a).Dot(segment)/length))
+	return a.Add(segment.Scale(t))
+}
+
+// closestOnCore function returns the point in the core of a circle or a
+// capsule closest to the given point.
+func closestOnCore(core []Vec, point Vec) Vec {
+	if len(core) == 1 {
+		return core[0]
+	}
+	return closestOnSegment(core[0], core[1], point)
+}
+
+// closestBetweenCores function returns the closest points between the cores
+// of two circles or capsules.
+func closestBetweenCores(a, b []Vec) (Vec, Vec) {
+	if len(a) == 1 {
+		return a[0], closestOnCore(b, a[0])
+	}
+	if len(b) == 1 {
+		return closestOnCore(a, b[0]), b[0]
+	}
+	// closest points between two segments are at an end point of one of them,
+	// unless the segments cross.
+	bestA, bestB := a[0], closestOnCore(b, a[0])
+	best := bestA.Sub(bestB).Len()
+	candidates := [][2]Vec{
+		{a[1], closestOnCore(b, a[1])},
+		{closestOnCore(a, b[0]), b[0]},
+		{closestOnCore(a, b[1]), b[1]},
+	}
+	for _, candidate := range candidates {
+		if distance := candidate[0].Sub(candidate[1]).Len(); distance < best {
+			bestA, bestB, best = candidate[0], candidate[1], distance
+		}
+	}
+	return bestA, bestB
+}
+
+// getRoundAxes function returns the axes to test between a circle or capsule
+// and other shape: from the core of the round shape to every point in the
+// core of the other shape.
+func getRoundAxes(round, other IShape) []Vec {
+	var axes []Vec
+	core := round.getCore()
+	if other.getRadius() > 0 {
+		a, b := closestBetweenCores(core, other.getCore())
+		return append(axes, b.Sub(a).Normalize())
+	}
+	for _, point := range other.getCore() {
+		axes = append(axes, point.Sub(closestOnCore(core, point)).Normalize())
+	}
+	return axes
+}
+
+// -----------------------------------------------------------------------------
+// Public functions
+// -----------------------------------------------------------------------------
+
+// Collide function checks if both shapes overlap using the separating axis
+// theorem. It returns the normal and the depth of the minimum translation
+// that moves shape a out of shape b, and false if the shapes do not overlap.
+// Shapes only touching at their edges do not overlap.
+func Collide(a, b IShape) (Vec, float64, bool) {
+	axes := append(a.getAxes(), b.getAxes()...)
+	if a.getRadius() > 0 {
+		axes = append(axes, getRoundAxes(a, b)...)
+	}
+	if b.getRadius() > 0 {
+		axes = append(axes, getRoundAxes(b, a)...)
+	}
+	normal, depth := Vec{}, math.Inf(1)
+	for _, axis := range axes {
+		if axis == (Vec{}) {
+			continue
+		}
+		minA, maxA := a.project(axis)
+		minB, maxB := b.project(axis)
+		overlap := math.Min(maxA, maxB) - math.Max(minA, minB)
+		if overlap <= Epsilon {
+			return Vec{}, 0, false
+		}
+		if overlap < depth {
+			normal, depth = axis, overlap
+		}
+	}
+	if math.IsInf(depth, 1) {
+		// concentric circles have no axis, so any direction separates them.
+		normal, depth = Vec{0, -1}, a.getRadius()+b.getRadius()
+	}
+	ax, ay := a.GetBounds().Center()
+	bx, by := b.GetBounds().Center()
+	if (Vec{ax - bx, ay - by}).Dot(normal) < 0 {
+		normal = normal.Scale(-1)
+	}
+	return normal, depth, true
+}
+
+// Overlaps function returns if both shapes overlap.
+func Overlaps(a, b IShape) bool {
+	_, _, ok := Collide(a, b)
+	return ok
+}
+
+var _ IShape = (*Circle)(nil)
+var _ IShape = (*Capsule)(nil)
+var _ IShape = (*Polygon)(nil)
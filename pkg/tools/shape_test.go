@@ -0,0 +1,59 @@
+package tools_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jrecuero/ebiplay/pkg/tools"
+)
+
+func TestCollide(t *testing.T) {
+	box := tools.NewBox(0, 0, 10, 10)
+	tests := []struct {
+		name    string
+		shape   tools.IShape
+		overlap bool
+	}{
+		{"box", tools.NewBox(5, 5, 10, 10), true},
+		{"touching box", tools.NewBox(10, 0, 10, 10), false},
+		{"circle", tools.NewCircle(12, 5, 3), true},
+		{"circle far", tools.NewCircle(15, 5, 3), false},
+		// circle near the corner overlaps the box bounds but not the box.
+		{"circle corner", tools.NewCircle(13, 13, 4), false},
+		{"capsule", tools.NewCapsule(12, -10, 12, 20, 3), true},
+		{"capsule far", tools.NewCapsule(14, -10, 14, 20, 3), false},
+		{"oriented box", tools.NewOrientedBox(15, 5, 8, 8, math.Pi/4), true},
+		{"oriented box far", tools.NewOrientedBox(16, 5, 8, 8, math.Pi/4), false},
+		{"polygon", tools.NewPolygon(tools.Vec{X: 8, Y: 5}, tools.Vec{X: 20, Y: 0}, tools.Vec{X: 20, Y: 10}), true},
+	}
+	for _, test := range tests {
+		if overlap := tools.Overlaps(test.shape, box); overlap != test.overlap {
+			t.Errorf("%s: overlap = %t, expected %t", test.name, overlap, test.overlap)
+		}
+	}
+}
+
+func TestCollideNormal(t *testing.T) {
+	box := tools.NewBox(0, 0, 10, 10)
+	normal, depth, ok := tools.Collide(tools.NewCircle(12, 5, 3), box)
+	if !ok {
+		t.Fatalf("circle expected to overlap the box")
+	}
+	if normal != (tools.Vec{X: 1, Y: 0}) || math.Abs(depth-1) > tools.Epsilon {
+		t.Errorf("(%v, %f), expected ({1 0}, 1)", normal, depth)
+	}
+	normal, _, _ = tools.Collide(box, tools.NewCircle(12, 5, 3))
+	if normal != (tools.Vec{X: -1, Y: 0}) {
+		t.Errorf("%v, expected {-1 0}", normal)
+	}
+}
+
+func TestShapeTransform(t *testing.T) {
+	shape := tools.NewBox(-5, -10, 10, 10).Transform(100, 50, math.Pi/2, 2)
+	bounds := shape.GetBounds()
+	expected := tools.NewRect(100, 40, 20, 20)
+	if math.Abs(bounds.X-expected.X) > tools.Epsilon || math.Abs(bounds.Y-expected.Y) > tools.Epsilon ||
+		math.Abs(bounds.W-expected.W) > tools.Epsilon || math.Abs(bounds.H-expected.H) > tools.Epsilon {
+		t.Errorf("%v, expected %v", bounds, expected)
+	}
+}
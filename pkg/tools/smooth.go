@@ -0,0 +1,33 @@
+// smooth.go contains frame rate independent smoothing used to follow moving
+// values.
+package tools
+
+import "math"
+
+// -----------------------------------------------------------------------------
+// Public functions
+// -----------------------------------------------------------------------------
+
+// SmoothLerp function moves current towards target by the fraction given by
+// the rate, in fraction per second, for the elapsed time in seconds. The
+// result does not depend on how the time is split between calls.
+func SmoothLerp(current, target, rate, dt float64) float64 {
+	return Lerp(current, target, 1-math.Exp(-rate*dt))
+}
+
+// SmoothDamp function moves current towards target like a critically damped
+// spring, which reaches the target in about the given smooth time in seconds
+// without overshooting. The velocity is kept between calls.
+func SmoothDamp(current, target float64, velocity *float64, smoothTime, dt float64) float64 {
+	if smoothTime <= 0 {
+		*velocity = 0
+		return target
+	}
+	omega := 2 / smoothTime
+	x := omega * dt
+	decay := 1 / (1 + x + 0.48*x*x + 0.235*x*x*x)
+	change := current - target
+	temp := (*velocity + omega*change) * dt
+	*velocity = (*velocity - omega*temp) * decay
+	return target + (change+temp)*decay
+}
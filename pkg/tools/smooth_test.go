@@ -0,0 +1,29 @@
+package tools_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jrecuero/ebiplay/pkg/tools"
+)
+
+func TestSmoothLerpFrameRate(t *testing.T) {
+	once := tools.SmoothLerp(0, 100, 5, 0.1)
+	twice := tools.SmoothLerp(tools.SmoothLerp(0, 100, 5, 0.05), 100, 5, 0.05)
+	if math.Abs(once-twice) > tools.Epsilon {
+		t.Errorf("%f and %f expected to be equal", once, twice)
+	}
+}
+
+func TestSmoothDamp(t *testing.T) {
+	value, velocity := 0.0, 0.0
+	for i := 0; i < 120; i++ {
+		value = tools.SmoothDamp(value, 100, &velocity, 0.25, 1.0/60)
+		if value > 100+tools.Epsilon {
+			t.Fatalf("step %d: %f overshoots the target", i, value)
+		}
+	}
+	if math.Abs(value-100) > 0.1 {
+		t.Errorf("%f, expected to reach 100", value)
+	}
+}